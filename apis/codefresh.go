@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	resourcev1alpha1 "crossplane-provider-codefresh/apis/resource/v1alpha1"
+	resourcev1beta1 "crossplane-provider-codefresh/apis/resource/v1beta1"
 	codefreshv1alpha1 "crossplane-provider-codefresh/apis/v1alpha1"
 )
 
@@ -29,6 +30,7 @@ func init() {
 	AddToSchemes = append(AddToSchemes,
 		codefreshv1alpha1.SchemeBuilder.AddToScheme,
 		resourcev1alpha1.SchemeBuilder.AddToScheme,
+		resourcev1beta1.SchemeBuilder.AddToScheme,
 	)
 }
 
@@ -19,11 +19,14 @@ limitations under the License.
 // NOTE: See the below link for details on what is happening here.
 // https://github.com/golang/go/wiki/Modules#how-can-i-track-tool-dependencies-for-a-module
 
-// Remove existing CRDs
-//go:generate rm -rf ../package/crds
+// Remove existing CRDs and webhook configurations
+//go:generate rm -rf ../package/crds ../package/webhookconfigurations
 
-// Generate deepcopy methodsets and CRD manifests
-//go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:crdVersions=v1 output:artifacts:config=../package/crds
+// Generate deepcopy methodsets, CRD manifests and webhook configurations
+//go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:crdVersions=v1 output:artifacts:config=../package/crds webhook output:webhook:artifacts:config=../package/webhookconfigurations
+
+// Enable webhook conversion on CRDs that serve more than one version
+//go:generate go run -tags generate ../hack/crd-conversion ../package/crds/resource.codefresh.crossplane.io_pipelines.yaml ../package/crds/resource.codefresh.crossplane.io_projects.yaml
 
 // Generate crossplane-runtime methodsets (resource.Claim, etc)
 //go:generate go run -tags generate github.com/crossplane/crossplane-tools/cmd/angryjet generate-methodsets --header-file=../hack/boilerplate.go.txt ./...
@@ -0,0 +1,33 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Types of CodeFresh entities that can be annotated.
+const (
+	AnnotationEntityPipeline = "pipeline"
+	AnnotationEntityProject  = "project"
+	AnnotationEntityBuild    = "build"
+)
+
+// An Annotation is a key/value pair attached to a CodeFresh entity, as
+// returned by the CodeFresh annotations API.
+type Annotation struct {
+	EntityID   string `json:"entityId"`
+	EntityType string `json:"entityType"`
+	Key        string `json:"key"`
+	Value      string `json:"value"`
+}
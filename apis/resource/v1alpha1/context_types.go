@@ -0,0 +1,34 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Context is a CodeFresh context, e.g. a git or secret configuration.
+type Context struct {
+	Metadata ContextMetadata `json:"metadata"`
+	Spec     ContextSpec     `json:"spec"`
+}
+
+// ContextMetadata identifies a Context.
+type ContextMetadata struct {
+	Name string `json:"name"`
+}
+
+// ContextSpec holds the type and data of a Context.
+type ContextSpec struct {
+	Type string            `json:"type"`
+	Data map[string]string `json:"data,omitempty"`
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeDrifted is the type of the condition that reports whether a resource in
+// CodeFresh differed from its spec when it was last observed.
+const TypeDrifted xpv1.ConditionType = "Drifted"
+
+// Reasons a resource has or has not drifted.
+const (
+	ReasonDriftDetected xpv1.ConditionReason = "DriftDetected"
+	ReasonNoDrift       xpv1.ConditionReason = "NoDrift"
+)
+
+// Drifted returns a condition that indicates the resource in CodeFresh
+// differs from its spec.
+func Drifted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDriftDetected,
+	}
+}
+
+// NotDrifted returns a condition that indicates the resource in CodeFresh
+// matches its spec.
+func NotDrifted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoDrift,
+	}
+}
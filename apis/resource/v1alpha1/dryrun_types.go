@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// AnnotationKeyDryRun puts a single resource in dry-run mode when set to
+// "true". In dry-run mode the provider observes the resource in CodeFresh and
+// records what it would do, but never creates, updates or deletes it.
+const AnnotationKeyDryRun = "resource.codefresh.crossplane.io/dry-run"
+
+// A PlannedAction is what the provider would do to a resource in CodeFresh
+// if it were not in dry-run mode.
+type PlannedAction string
+
+// Planned actions.
+const (
+	PlannedActionNone   PlannedAction = "None"
+	PlannedActionCreate PlannedAction = "Create"
+	PlannedActionUpdate PlannedAction = "Update"
+	PlannedActionDelete PlannedAction = "Delete"
+)
+
+// GetPlannedAction of this Pipeline.
+func (mg *Pipeline) GetPlannedAction() PlannedAction {
+	return mg.Status.AtProvider.PlannedAction
+}
+
+// SetPlannedAction of this Pipeline.
+func (mg *Pipeline) SetPlannedAction(a PlannedAction) {
+	mg.Status.AtProvider.PlannedAction = a
+}
+
+// GetPlannedAction of this Project.
+func (mg *Project) GetPlannedAction() PlannedAction {
+	return mg.Status.AtProvider.PlannedAction
+}
+
+// SetPlannedAction of this Project.
+func (mg *Project) SetPlannedAction(a PlannedAction) {
+	mg.Status.AtProvider.PlannedAction = a
+}
+
+// GetPlannedAction of this PipelineRun.
+func (mg *PipelineRun) GetPlannedAction() PlannedAction {
+	return mg.Status.AtProvider.PlannedAction
+}
+
+// SetPlannedAction of this PipelineRun.
+func (mg *PipelineRun) SetPlannedAction(a PlannedAction) {
+	mg.Status.AtProvider.PlannedAction = a
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// A ManagementMode determines whether the provider owns all entries of the
+// tags, variables and triggers it manages, or only those it declares.
+type ManagementMode string
+
+// Management modes.
+const (
+	// ManagementModeAuthoritative makes CodeFresh hold exactly the declared
+	// entries, removing any others.
+	ManagementModeAuthoritative ManagementMode = "Authoritative"
+	// ManagementModeAdditive manages only the declared entries, leaving
+	// entries added elsewhere, for example in the CodeFresh UI, in place.
+	ManagementModeAdditive ManagementMode = "Additive"
+)
+
+// OwnedEntries are the entries the provider declared, and so owns, when it
+// last synced a resource in Additive management mode. An owned entry that is
+// no longer declared is removed; other entries that are not declared are left
+// in place.
+type OwnedEntries struct {
+	// Tags owned by the provider.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// Variables owned by the provider, by key.
+	// +optional
+	Variables []string `json:"variables,omitempty"`
+	// Triggers owned by the provider, by name.
+	// +optional
+	Triggers []string `json:"triggers,omitempty"`
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+)
+
+// CodeFresh runs the steps of a pipeline in the order of the keys of its
+// steps object. JSON objects decoded into Go maps lose that order, so steps
+// are encoded and decoded member by member.
+
+// AnnotationKeyStepOrder records the order of a Pipeline's steps, whose steps
+// are a map, as a JSON list of step names, for example ["clone","build"].
+// It is written when a v1beta1 Pipeline, whose steps are a list, is stored,
+// and read when the steps are sent to CodeFresh or converted back to
+// v1beta1. Steps it does not list run after those it does, in name order.
+const AnnotationKeyStepOrder = "resource.codefresh.crossplane.io/step-order"
+
+const errStepsNotObject = "steps must be a JSON object"
+
+// GetStepOrder returns the step names recorded in the step order annotation
+// of the Pipeline, if any.
+func (mg *Pipeline) GetStepOrder() []string {
+	var names []string
+	_ = json.Unmarshal([]byte(mg.GetAnnotations()[AnnotationKeyStepOrder]), &names)
+	return names
+}
+
+// SetStepOrder records the supplied step names in the step order annotation
+// of the Pipeline, or removes the annotation when there are none.
+func (mg *Pipeline) SetStepOrder(names []string) {
+	if len(names) == 0 {
+		delete(mg.GetAnnotations(), AnnotationKeyStepOrder)
+		return
+	}
+	b, _ := json.Marshal(names)
+	meta.AddAnnotations(mg, map[string]string{AnnotationKeyStepOrder: string(b)})
+}
+
+// SortSteps returns the supplied steps named by their keys, in the supplied
+// order. Steps missing from the order follow in name order.
+func SortSteps(steps map[string]PipelineStep, order []string) []PipelineStep {
+	if steps == nil {
+		return nil
+	}
+	out := make([]PipelineStep, 0, len(steps))
+	seen := make(map[string]bool, len(steps))
+	for _, n := range order {
+		if st, ok := steps[n]; ok && !seen[n] {
+			st.Name = n
+			out = append(out, st)
+			seen[n] = true
+		}
+	}
+	rest := make([]string, 0, len(steps)-len(out))
+	for n := range steps {
+		if !seen[n] {
+			rest = append(rest, n)
+		}
+	}
+	sort.Strings(rest)
+	for _, n := range rest {
+		st := steps[n]
+		st.Name = n
+		out = append(out, st)
+	}
+	return out
+}
+
+// MarshalJSON encodes the spec with its ordered steps as an object keyed by
+// step name, in the order the steps run, and its termination policy as rules. A
+// termination policy or external resources that are set but empty are sent
+// as empty lists, which clear them, rather than omitted. A pipeline loads its
+// steps either inline or from a spec template, so whichever of the two is
+// sent, the other is sent as null, which clears it.
+func (in PipelineSpecRequest) MarshalJSON() ([]byte, error) {
+	type spec PipelineSpecStruct
+	var steps, template json.RawMessage
+	var err error
+	switch {
+	case in.SpecTemplate != nil:
+		if template, err = json.Marshal(in.SpecTemplate); err != nil {
+			return nil, err
+		}
+		steps = json.RawMessage("null")
+	case in.OrderedSteps != nil:
+		steps, err = encodeObject(len(in.OrderedSteps), func(i int) (string, any) {
+			return in.OrderedSteps[i].Name, in.OrderedSteps[i]
+		})
+		if err != nil {
+			return nil, err
+		}
+		template = json.RawMessage("null")
+	}
+	var rules *[]PipelineTerminationRule
+	if in.TerminationPolicy != nil {
+		r := append([]PipelineTerminationRule{}, in.TerminationPolicy.Rules()...)
+		rules = &r
+	}
+	var resources *[]PipelineExternalResource
+	if in.ExternalResources != nil {
+		resources = &in.ExternalResources
+	}
+	return json.Marshal(struct {
+		spec
+		Steps             json.RawMessage             `json:"steps,omitempty"`
+		SpecTemplate      json.RawMessage             `json:"specTemplate,omitempty"`
+		TerminationPolicy *[]PipelineTerminationRule  `json:"terminationPolicy,omitempty"`
+		ExternalResources *[]PipelineExternalResource `json:"externalResources,omitempty"`
+	}{spec: spec(in.PipelineSpecStruct), Steps: steps, SpecTemplate: template, TerminationPolicy: rules, ExternalResources: resources})
+}
+
+// MarshalJSON encodes the steps as an object keyed by step name, in the order
+// the steps run.
+func (in PipelineStepsResponse) MarshalJSON() ([]byte, error) {
+	if in == nil {
+		return []byte("null"), nil
+	}
+	return encodeObject(len(in), func(i int) (string, any) {
+		return in[i].Name, in[i]
+	})
+}
+
+// UnmarshalJSON decodes steps from an object keyed by step name, keeping the
+// order of its keys.
+func (in *PipelineStepsResponse) UnmarshalJSON(data []byte) error {
+	*in = nil
+	return decodeObject(data, func(name string, value json.RawMessage) error {
+		step := PipelineStepResponse{}
+		if err := json.Unmarshal(value, &step); err != nil {
+			return err
+		}
+		step.Name = name
+		*in = append(*in, step)
+		return nil
+	})
+}
+
+// decodeObject calls fn for each member of the JSON object in data, in the
+// order they appear. A null object has no members.
+func decodeObject(data []byte, fn func(key string, value json.RawMessage) error) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	t, err := d.Token()
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	if delim, ok := t.(json.Delim); !ok || delim != '{' {
+		return errors.New(errStepsNotObject)
+	}
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := d.Decode(&value); err != nil {
+			return err
+		}
+		if err := fn(t.(string), value); err != nil {
+			return err
+		}
+	}
+	_, err = d.Token()
+	return err
+}
+
+// encodeObject encodes a JSON object of n members, in the order member
+// returns them.
+func encodeObject(n int, member func(i int) (string, any)) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i := 0; i < n; i++ {
+		k, v := member(i)
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
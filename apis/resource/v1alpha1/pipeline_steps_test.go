@@ -0,0 +1,233 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSortSteps(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		steps  map[string]PipelineStep
+		order  []string
+		want   []PipelineStep
+	}{
+		"Ordered": {
+			reason: "Steps should follow the recorded order, named by their keys.",
+			steps:  map[string]PipelineStep{"build": {}, "test": {Values: []KeyValue{{Key: "stage", Value: "test"}}}},
+			order:  []string{"test", "build"},
+			want:   []PipelineStep{{Name: "test", Values: []KeyValue{{Key: "stage", Value: "test"}}}, {Name: "build"}},
+		},
+		"Unrecorded": {
+			reason: "Steps missing from the order should follow in name order, and recorded steps that are gone should be skipped.",
+			steps:  map[string]PipelineStep{"build": {}, "test": {}, "deploy": {}},
+			order:  []string{"test", "gone", "test"},
+			want:   []PipelineStep{{Name: "test"}, {Name: "build"}, {Name: "deploy"}},
+		},
+		"NoSteps": {
+			reason: "Unset steps should stay unset.",
+			order:  []string{"test"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SortSteps(tc.steps, tc.order)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSortSteps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPipelineStepOrder(t *testing.T) {
+	p := &Pipeline{}
+	p.SetStepOrder([]string{"test", "build"})
+	if diff := cmp.Diff(`["test","build"]`, p.GetAnnotations()[AnnotationKeyStepOrder]); diff != "" {
+		t.Errorf("SetStepOrder(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"test", "build"}, p.GetStepOrder()); diff != "" {
+		t.Errorf("GetStepOrder(): -want, +got:\n%s", diff)
+	}
+
+	p.SetStepOrder(nil)
+	if _, ok := p.GetAnnotations()[AnnotationKeyStepOrder]; ok {
+		t.Error("SetStepOrder(nil): want annotation removed")
+	}
+	if got := p.GetStepOrder(); got != nil {
+		t.Errorf("GetStepOrder(): want no order, got %v", got)
+	}
+}
+
+func TestPipelineSpecRequestMarshalJSON(t *testing.T) {
+	in := PipelineSpecRequest{
+		PipelineSpecStruct: PipelineSpecStruct{
+			Stages: []string{"build"},
+			Steps:  map[string]PipelineStep{"a": {}, "build": {}, "test": {}},
+		},
+		OrderedSteps: []PipelineStep{{Name: "test"}, {Name: "build"}, {Name: "a"}},
+	}
+	got, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+	want := `{"stages":["build"],"steps":{"test":{"name":"test","values":null},"build":{"name":"build","values":null},"a":{"name":"a","values":null}},"specTemplate":null}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("steps should be sent as an object in run order: -want, +got:\n%s", diff)
+	}
+}
+
+func TestPipelineSpecRequestMarshalStepSource(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     PipelineSpecRequest
+		want   string
+	}{
+		"Unset": {
+			reason: "Neither steps nor a spec template should be sent when both are unmanaged.",
+			want:   `{}`,
+		},
+		"InlineSteps": {
+			reason: "Inline steps should clear the spec template.",
+			in:     PipelineSpecRequest{OrderedSteps: []PipelineStep{{Name: "build"}}},
+			want:   `{"steps":{"build":{"name":"build","values":null}},"specTemplate":null}`,
+		},
+		"SpecTemplate": {
+			reason: "A spec template should clear the inline steps.",
+			in: PipelineSpecRequest{PipelineSpecStruct: PipelineSpecStruct{
+				SpecTemplate: &PipelineSpecTemplate{Repo: "org/app", Revision: "main"},
+			}},
+			want: `{"steps":null,"specTemplate":{"repo":"org/app","revision":"main"}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(tc.in)
+			if err != nil {
+				t.Fatalf("\n%s\njson.Marshal(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\njson.Marshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPipelineSpecRequestMarshalTerminationPolicy(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     *PipelineTerminationPolicy
+		want   string
+	}{
+		"Unset": {
+			reason: "An unset policy should not be sent.",
+			want:   `{}`,
+		},
+		"Empty": {
+			reason: "A policy without settings should be sent as an empty list of rules.",
+			in:     &PipelineTerminationPolicy{},
+			want:   `{"terminationPolicy":[]}`,
+		},
+		"Rules": {
+			reason: "Each setting of the policy should be sent as a rule.",
+			in: &PipelineTerminationPolicy{
+				TerminatePreviousBuilds: &TerminatePreviousBuilds{BranchName: "main", IgnoreTrigger: true},
+				TerminateChildBuilds:    true,
+			},
+			want: `{"terminationPolicy":[{"type":"branch","event":"onCreate","branchName":"main","ignoreTrigger":true},{"type":"annotation","event":"onTerminate","key":"cf_predecessor"}]}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(PipelineSpecRequest{PipelineSpecStruct: PipelineSpecStruct{TerminationPolicy: tc.in}})
+			if err != nil {
+				t.Fatalf("\n%s\njson.Marshal(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\njson.Marshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPipelineSpecRequestMarshalExternalResources(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     []PipelineExternalResource
+		want   string
+	}{
+		"Unset": {
+			reason: "Unset external resources should not be sent.",
+			want:   `{}`,
+		},
+		"Empty": {
+			reason: "An empty list of external resources should be sent, so that it clears them.",
+			in:     []PipelineExternalResource{},
+			want:   `{"externalResources":[]}`,
+		},
+		"Resources": {
+			reason: "External resources should be sent in order.",
+			in: []PipelineExternalResource{
+				{Type: ExternalResourceTypeGit, Repo: "org/config", Revision: "main", Source: "ci", Destination: "ci", IsFolder: true},
+				{Repo: "org/config", Revision: "v1", Source: "a.yml", Destination: "a.yml", Context: "github"},
+			},
+			want: `{"externalResources":[{"type":"git","repo":"org/config","revision":"main","source":"ci","destination":"ci","isFolder":true},{"repo":"org/config","revision":"v1","source":"a.yml","destination":"a.yml","context":"github"}]}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(PipelineSpecRequest{PipelineSpecStruct: PipelineSpecStruct{ExternalResources: tc.in}})
+			if err != nil {
+				t.Fatalf("\n%s\njson.Marshal(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\njson.Marshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPipelineStepsResponseRoundTrip(t *testing.T) {
+	in := `{"test":{"title":"Test","type":"freestyle","workingDirectory":"","arguments":null},"build":{"title":"Build","type":"build","workingDirectory":"","arguments":null}}`
+	var got PipelineStepsResponse
+	if err := json.Unmarshal([]byte(in), &got); err != nil {
+		t.Fatalf("json.Unmarshal(...): %v", err)
+	}
+	want := PipelineStepsResponse{{Name: "test", Title: "Test", Type: "freestyle"}, {Name: "build", Title: "Build", Type: "build"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("json.Unmarshal(...): -want, +got:\n%s", diff)
+	}
+
+	out, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %v", err)
+	}
+	if diff := cmp.Diff(in, string(out)); diff != "" {
+		t.Errorf("json.Marshal(...): -want, +got:\n%s", diff)
+	}
+
+	if err := json.Unmarshal([]byte(`["test"]`), &got); err == nil {
+		t.Error("json.Unmarshal(...): want error for steps that are not an object")
+	}
+}
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -86,8 +87,18 @@ type PipelineOptions struct {
 
 // PipelineVariable as per CodeFresh API spec.
 type PipelineVariable struct {
-	Key   string `json:"key"`
+	Key string `json:"key"`
+	// Value of the variable. Must be empty when ValueFrom is set.
+	// +optional
 	Value string `json:"value"`
+	// ValueFrom reads the value of the variable from a Secret or ConfigMap
+	// each time the pipeline is reconciled.
+	// +optional
+	ValueFrom *VariableSource `json:"valueFrom,omitempty"`
+	// Encrypted stores the variable encrypted in CodeFresh, which then masks
+	// its value.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
 }
 
 // PipelineTrigger as per CodeFresh API spec.
@@ -126,6 +137,109 @@ type PipelineCronTrigger struct {
 
 type PipelineMetadata struct {
 	Name string `json:"name"`
+	// Labels of the pipeline in CodeFresh, such as its tags. Labels are left
+	// unmanaged when unset.
+	// +optional
+	Labels map[string][]string `json:"labels,omitempty"`
+	// Annotations to set on the pipeline in CodeFresh. Annotations that are
+	// not listed are left alone.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Deprecate marks the pipeline deprecated in CodeFresh. Deprecation is
+	// left unmanaged when unset.
+	// +optional
+	Deprecate *PipelineDeprecate `json:"deprecate,omitempty"`
+}
+
+// LabelKeyTags is the label CodeFresh keeps the tags of a pipeline in.
+const LabelKeyTags = "tags"
+
+// PipelineDeprecate are the deprecated settings of a pipeline. A pipeline
+// with any of them set is reported deprecated by CodeFresh.
+type PipelineDeprecate struct {
+	// ApplicationPort is the port of the application of a legacy pipeline.
+	// +optional
+	ApplicationPort *string `json:"applicationPort,omitempty"`
+	// RepoPipeline marks a pipeline that was converted from a legacy
+	// repository pipeline.
+	// +optional
+	RepoPipeline *bool `json:"repoPipeline,omitempty"`
+}
+
+// IsSet returns true if any deprecated setting is set.
+func (d *PipelineDeprecate) IsSet() bool {
+	return d != nil && (d.ApplicationPort != nil || d.RepoPipeline != nil)
+}
+
+// TypeDeprecated is the type of the condition that reports whether CodeFresh
+// considers a pipeline deprecated.
+const TypeDeprecated xpv1.ConditionType = "Deprecated"
+
+// Reasons a pipeline is or is not deprecated.
+const (
+	ReasonDeprecated    xpv1.ConditionReason = "DeprecatedInCodeFresh"
+	ReasonNotDeprecated xpv1.ConditionReason = "NotDeprecated"
+)
+
+// Deprecated returns a condition that indicates CodeFresh reports the
+// pipeline deprecated.
+func Deprecated() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeprecated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeprecated,
+	}
+}
+
+// NotDeprecated returns a condition that indicates the pipeline is no longer
+// deprecated.
+func NotDeprecated() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeprecated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotDeprecated,
+	}
+}
+
+// TypeConflictDetected is the type of the condition that reports whether an
+// update of a pipeline was rejected because the pipeline changed in CodeFresh
+// since the provider last applied it.
+const TypeConflictDetected xpv1.ConditionType = "ConflictDetected"
+
+// Reasons an update of a pipeline does or does not conflict.
+const (
+	ReasonRevisionConflict xpv1.ConditionReason = "RevisionConflict"
+	ReasonNoConflict       xpv1.ConditionReason = "NoConflict"
+)
+
+// ConflictDetected returns a condition that indicates an update of the
+// pipeline was rejected because it changed in CodeFresh since the provider
+// last applied it.
+func ConflictDetected() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConflictDetected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRevisionConflict,
+	}
+}
+
+// AnnotationKeyAcceptRevision is the annotation with which a revision of a
+// pipeline that conflicted with an update is accepted, so that the pipeline
+// is updated against it. Its value is the revision to accept.
+const AnnotationKeyAcceptRevision = "resource.codefresh.crossplane.io/accept-revision"
+
+// NoConflict returns a condition that indicates the last update of the
+// pipeline was applied.
+func NoConflict() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConflictDetected,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoConflict,
+	}
 }
 
 type NullType struct{}
@@ -135,40 +249,264 @@ func (n NullType) MarshalJSON() ([]byte, error) {
 }
 
 type PipelineSpecStruct struct {
-	Triggers     []PipelineTrigger       `json:"triggers,omitempty"`
-	CronTriggers []PipelineCronTrigger   `json:"cronTriggers,omitempty"`
-	Steps        map[string]PipelineStep `json:"steps,omitempty"`
-	Stages       []string                `json:"stages,omitempty"`
-	Variables    []PipelineVariable      `json:"variables,omitempty"`
-	Options      PipelineOptions         `json:"options,omitempty"`
+	Triggers     []PipelineTrigger     `json:"triggers,omitempty"`
+	CronTriggers []PipelineCronTrigger `json:"cronTriggers,omitempty"`
+	// Steps of the pipeline, keyed by step name. CodeFresh runs them in the
+	// order listed by the resource.codefresh.crossplane.io/step-order
+	// annotation, a JSON list of step names, and those it does not list
+	// after them in name order. Steps are left unmanaged when unset.
+	// Mutually exclusive with SpecTemplate.
+	//
+	// A map does not keep the order of its keys, so ordering steps in this
+	// version is deprecated: use v1beta1, whose steps are an ordered list
+	// and which records their order in the annotation.
+	// +optional
+	Steps map[string]PipelineStep `json:"steps,omitempty"`
+	// SpecTemplate loads the steps of the pipeline from a YAML file in a git
+	// repository instead. Mutually exclusive with Steps.
+	// +optional
+	SpecTemplate *PipelineSpecTemplate `json:"specTemplate,omitempty"`
+	// Stages is late-initialized from the default stages CodeFresh assigns
+	// when none are given.
+	// +optional
+	Stages    []string           `json:"stages,omitempty"`
+	Variables []PipelineVariable `json:"variables,omitempty"`
+	// Options is late-initialized from the options CodeFresh applies by
+	// default when none are given.
+	// +optional
+	Options *PipelineOptions `json:"options,omitempty"`
 	/*	Contexts     [][]PipelineContext     `json:"contexts"`*/
+
+	// Concurrency is the maximum number of builds of the pipeline that run
+	// at once. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Concurrency *int `json:"concurrency,omitempty"`
+	// TriggerConcurrency is the maximum number of builds of each trigger of
+	// the pipeline that run at once. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TriggerConcurrency *int `json:"triggerConcurrency,omitempty"`
+	// BranchConcurrency is the maximum number of builds of each branch that
+	// run at once. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BranchConcurrency *int `json:"branchConcurrency,omitempty"`
+	// Priority of the builds of the pipeline; pending builds with a higher
+	// priority start first. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=-100
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Priority *int `json:"priority,omitempty"`
+	// TerminationPolicy terminates running builds of the pipeline when
+	// another build starts or is terminated. Left unmanaged when unset.
+	// +optional
+	TerminationPolicy *PipelineTerminationPolicy `json:"terminationPolicy,omitempty"`
+	// ExternalResources are files CodeFresh copies from git repositories
+	// into the shared volume before each build, in order. Left unmanaged when
+	// unset.
+	// +optional
+	ExternalResources []PipelineExternalResource `json:"externalResources,omitempty"`
+}
+
+// ExternalResourceTypeGit is the type of an external resource copied from a
+// git repository.
+const ExternalResourceTypeGit = "git"
+
+// A PipelineExternalResource is a file or folder CodeFresh copies from a git
+// repository into the shared volume of a build.
+type PipelineExternalResource struct {
+	// Type of the resource. Only git is supported.
+	// +kubebuilder:validation:Enum=git
+	// +kubebuilder:default=git
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Repo is the repository to copy from, in the form owner/name.
+	Repo string `json:"repo"`
+	// Revision is the branch, tag or commit to copy from.
+	Revision string `json:"revision"`
+	// Source is the path of the file or folder in the repository.
+	Source string `json:"source"`
+	// Destination is the path in the shared volume to copy to, relative to
+	// /codefresh/volume.
+	Destination string `json:"destination"`
+	// IsFolder copies the folder at Source rather than a single file.
+	// +optional
+	IsFolder bool `json:"isFolder,omitempty"`
+	// Context is the name of the git integration CodeFresh reads the
+	// repository with. Defaults to the default git integration of the
+	// account.
+	// +optional
+	Context string `json:"context,omitempty"`
+}
+
+// SpecTemplateLocationGit is the location of a spec template loaded from a
+// git repository.
+const SpecTemplateLocationGit = "git"
+
+// SpecTemplatePathDefault is the path of a spec template in its repository
+// when none is given.
+const SpecTemplatePathDefault = "./codefresh.yml"
+
+// A PipelineSpecTemplate locates the YAML file a pipeline loads its steps
+// from, such as the codefresh.yml of an application repository.
+type PipelineSpecTemplate struct {
+	// Location of the file. Only git is supported.
+	// +kubebuilder:validation:Enum=git
+	// +kubebuilder:default=git
+	// +optional
+	Location string `json:"location,omitempty"`
+	// Repo is the repository of the file, in the form owner/name.
+	Repo string `json:"repo"`
+	// Path of the file in the repository.
+	// +kubebuilder:default="./codefresh.yml"
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Revision is the branch, tag or commit to load the file from.
+	Revision string `json:"revision"`
+	// Context is the name of the git integration CodeFresh reads the
+	// repository with. Defaults to the default git integration of the
+	// account.
+	// +optional
+	Context string `json:"context,omitempty"`
+}
+
+// A PipelineTerminationPolicy decides which running builds CodeFresh
+// terminates. A policy without settings terminates no builds.
+type PipelineTerminationPolicy struct {
+	// TerminatePreviousBuilds terminates the running builds of the same
+	// branch once a new build is created.
+	// +optional
+	TerminatePreviousBuilds *TerminatePreviousBuilds `json:"terminatePreviousBuilds,omitempty"`
+	// TerminateChildBuilds terminates the builds a build started once it is
+	// terminated.
+	// +optional
+	TerminateChildBuilds bool `json:"terminateChildBuilds,omitempty"`
+}
+
+// TerminatePreviousBuilds selects the running builds to terminate once a new
+// build is created.
+type TerminatePreviousBuilds struct {
+	// BranchName only terminates previous builds of branches matching this
+	// regex. Defaults to the branch of the new build.
+	// +optional
+	BranchName string `json:"branchName,omitempty"`
+	// IgnoreTrigger also terminates previous builds of other triggers.
+	// +optional
+	IgnoreTrigger bool `json:"ignoreTrigger,omitempty"`
+	// IgnoreBranch terminates previous builds of every branch.
+	// +optional
+	IgnoreBranch bool `json:"ignoreBranch,omitempty"`
+}
+
+// Types and events of the rules of a CodeFresh termination policy.
+const (
+	TerminationRuleTypeBranch       = "branch"
+	TerminationRuleTypeAnnotation   = "annotation"
+	TerminationRuleEventOnCreate    = "onCreate"
+	TerminationRuleEventOnTerminate = "onTerminate"
+
+	// TerminationRuleKeyPredecessor is the build annotation CodeFresh links
+	// child builds to their parent with.
+	TerminationRuleKeyPredecessor = "cf_predecessor"
+)
+
+// A PipelineTerminationRule is a rule of a termination policy as CodeFresh
+// represents it.
+type PipelineTerminationRule struct {
+	Type          string `json:"type"`
+	Event         string `json:"event"`
+	Key           string `json:"key,omitempty"`
+	BranchName    string `json:"branchName,omitempty"`
+	IgnoreTrigger bool   `json:"ignoreTrigger,omitempty"`
+	IgnoreBranch  bool   `json:"ignoreBranch,omitempty"`
+}
+
+// Rules returns the policy as CodeFresh termination rules.
+func (in *PipelineTerminationPolicy) Rules() []PipelineTerminationRule {
+	if in == nil {
+		return nil
+	}
+	var out []PipelineTerminationRule
+	if p := in.TerminatePreviousBuilds; p != nil {
+		out = append(out, PipelineTerminationRule{
+			Type:          TerminationRuleTypeBranch,
+			Event:         TerminationRuleEventOnCreate,
+			BranchName:    p.BranchName,
+			IgnoreTrigger: p.IgnoreTrigger,
+			IgnoreBranch:  p.IgnoreBranch,
+		})
+	}
+	if in.TerminateChildBuilds {
+		out = append(out, PipelineTerminationRule{
+			Type:  TerminationRuleTypeAnnotation,
+			Event: TerminationRuleEventOnTerminate,
+			Key:   TerminationRuleKeyPredecessor,
+		})
+	}
+	return out
 }
 
 // PipelineParameters are the configurable fields of a Pipeline.
 type PipelineParameters struct {
 	Metadata PipelineMetadata   `json:"metadata"`
 	Spec     PipelineSpecStruct `json:"spec"`
+	// MirrorLabels are the keys of labels of this Pipeline to mirror into
+	// CodeFresh annotations, for example an owner team or cost center.
+	// Annotations in metadata take precedence over mirrored labels.
+	// +optional
+	MirrorLabels []string `json:"mirrorLabels,omitempty"`
+	// ManagementMode determines whether the tags, variables and triggers of
+	// the pipeline are replaced by the declared ones (Authoritative), or only
+	// the declared ones are managed, leaving others in place (Additive).
+	// Defaults to Authoritative.
+	// +kubebuilder:validation:Enum=Authoritative;Additive
+	// +optional
+	ManagementMode ManagementMode `json:"managementMode,omitempty"`
+}
+
+// PipelineSpecRequest is a PipelineSpecStruct as sent to CodeFresh, which
+// expects the steps as an object keyed by step name in the order they run.
+type PipelineSpecRequest struct {
+	PipelineSpecStruct `json:",inline"`
+
+	// OrderedSteps are sent instead of the steps of the spec, in order.
+	OrderedSteps []PipelineStep `json:"-"`
 }
 
 // PipelineStepResponse defines a step in the Pipeline response.
 type PipelineStepResponse struct {
+	// Name of the step, i.e. its key in the steps object.
+	Name             string              `json:"-"`
 	Title            string              `json:"title"`
 	Type             string              `json:"type"`
 	WorkingDirectory string              `json:"workingDirectory"`
 	Arguments        map[string][]string `json:"arguments"`
+	// Values of the step, as sent by the provider.
+	Values []KeyValue `json:"values,omitempty"`
 }
 
 // PipelineSpecResponse defines the spec part of the Pipeline response.
 type PipelineSpecResponse struct {
-	Triggers          map[string][]string             `json:"triggers"`
-	Stages            []string                        `json:"stages"`
-	Variables         map[string][]string             `json:"variables"`
-	Contexts          map[string][]string             `json:"contexts"`
-	TerminationPolicy map[string][]string             `json:"terminationPolicy"`
-	ExternalResources map[string][]string             `json:"externalResources"`
-	Steps             map[string]PipelineStepResponse `json:"steps"`
+	Triggers           []PipelineTrigger          `json:"triggers"`
+	Stages             []string                   `json:"stages"`
+	Variables          []PipelineVariable         `json:"variables"`
+	Options            *PipelineOptions           `json:"options"`
+	Contexts           []string                   `json:"contexts"`
+	SpecTemplate       *PipelineSpecTemplate      `json:"specTemplate,omitempty"`
+	Concurrency        *int                       `json:"concurrency,omitempty"`
+	TriggerConcurrency *int                       `json:"triggerConcurrency,omitempty"`
+	BranchConcurrency  *int                       `json:"branchConcurrency,omitempty"`
+	Priority           *int                       `json:"priority,omitempty"`
+	TerminationPolicy  []PipelineTerminationRule  `json:"terminationPolicy"`
+	ExternalResources  []PipelineExternalResource `json:"externalResources"`
+	Steps              PipelineStepsResponse      `json:"steps"`
 }
 
+// PipelineStepsResponse are the steps of a pipeline response, in the order
+// CodeFresh runs them.
+type PipelineStepsResponse []PipelineStepResponse
+
 // PipelineMetadata holds metadata information of a pipeline.
 type PipelineMetadataResponse struct {
 	Name               string              `json:"name"`
@@ -178,7 +516,7 @@ type PipelineMetadataResponse struct {
 	AccountId          string              `json:"accountId"`
 	CreatedAt          string              `json:"created_at"`
 	UpdatedAt          string              `json:"updated_at"`
-	Deprecate          map[string][]string `json:"deprecate"`
+	Deprecate          *PipelineDeprecate  `json:"deprecate"`
 	Labels             map[string][]string `json:"labels"`
 	OriginalYamlString string              `json:"originalYamlString"`
 	ID                 string              `json:"id"`
@@ -189,7 +527,7 @@ type PipelineDocument struct {
 	Metadata     PipelineMetadataResponse `json:"metadata"`
 	Version      string                   `json:"version"`
 	Kind         string                   `json:"kind"`
-	Spec         PipelineSpec             `json:"spec"`
+	Spec         PipelineSpecResponse     `json:"spec"`
 	LastExecuted string                   `json:"last_executed"`
 }
 
@@ -199,8 +537,8 @@ type PipelineDetails struct {
 }
 
 type PipelineCreateParams struct {
-	Metadata PipelineMetadata   `json:"metadata"`
-	Spec     PipelineSpecStruct `json:"spec"`
+	Metadata PipelineMetadata    `json:"metadata"`
+	Spec     PipelineSpecRequest `json:"spec"`
 }
 
 type PipelineResponsoneMetaData struct {
@@ -221,7 +559,7 @@ type CreatePipelineResponse struct {
 	Metadata PipelineResponsoneMetaData `json:"metadata"`
 	Version  string                     `json:"version"`
 	Kind     string                     `json:"kind"`
-	Spec     PipelineSpec               `json:"spec"`
+	Spec     PipelineSpecResponse       `json:"spec"`
 }
 
 // PipelineObservation are the observable fields of a Pipeline.
@@ -230,6 +568,52 @@ type PipelineObservation struct {
 	// Name    string `json:"name"`
 	Version string `json:"version"`
 	Kind    string `json:"kind"`
+	// VariableHashes are the hashes of the values last sent for encrypted
+	// variables of the spec, by key. CodeFresh masks encrypted values, so
+	// they are compared by hash instead.
+	// +optional
+	VariableHashes map[string]string `json:"variableHashes,omitempty"`
+	// TriggerVariableHashes are the hashes of the values last sent for
+	// encrypted variables of the triggers, by trigger name and key joined
+	// with a slash.
+	// +optional
+	TriggerVariableHashes map[string]string `json:"triggerVariableHashes,omitempty"`
+	// Owned are the tags, variables and triggers the provider owns in
+	// Additive management mode.
+	// +optional
+	Owned *OwnedEntries `json:"owned,omitempty"`
+	// Revision is the revision of the pipeline in CodeFresh that the
+	// provider last observed.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// AppliedRevision is the revision of the pipeline in CodeFresh that the
+	// provider last created or updated. Updates are made against it, so that
+	// a change made elsewhere since is not overwritten.
+	// +optional
+	AppliedRevision int `json:"appliedRevision,omitempty"`
+	// ConflictGeneration is the generation of the pipeline when an update
+	// was last rejected because the pipeline changed elsewhere. The change is
+	// overwritten once the generation differs, or its revision is accepted
+	// with the accept-revision annotation. It is zero once an update
+	// succeeds.
+	// +optional
+	ConflictGeneration int64 `json:"conflictGeneration,omitempty"`
+	// AnnotationKeys are the keys of the CodeFresh annotations of the
+	// pipeline that the provider manages. An annotation whose key is no
+	// longer desired, for example because its mirrored label was removed, is
+	// deleted.
+	// +optional
+	AnnotationKeys []string `json:"annotationKeys,omitempty"`
+	// Drift summarizes how the pipeline in CodeFresh differed from the spec
+	// when it was last found out of date, truncated to 1KiB. Secret values
+	// are redacted. It is cleared once the pipeline is up to date.
+	// +optional
+	Drift string `json:"drift,omitempty"`
+	// PlannedAction is what the provider would do to the pipeline in CodeFresh,
+	// recorded only while the resource is in dry-run mode.
+	// +kubebuilder:validation:Enum=None;Create;Update;Delete
+	// +optional
+	PlannedAction PlannedAction `json:"plannedAction,omitempty"`
 }
 
 // A PipelineSpec defines the desired state of a Pipeline.
@@ -250,8 +634,11 @@ type PipelineStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="DEPRECATED",type="string",JSONPath=".status.conditions[?(@.type=='Deprecated')].status",priority=1
+// +kubebuilder:printcolumn:name="DRIFTED",type="string",JSONPath=".status.conditions[?(@.type=='Drifted')].status",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,codefresh}
 type Pipeline struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -0,0 +1,280 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	errNotPipeline = "object is not a Pipeline"
+
+	stepStageKey = "stage"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-resource-codefresh-crossplane-io-v1alpha1-pipeline,mutating=false,failurePolicy=fail,groups=resource.codefresh.crossplane.io,resources=pipelines,versions=v1alpha1,name=pipelines.resource.codefresh.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the validating webhook of Pipeline with
+// the supplied manager.
+func (p *Pipeline) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(p).
+		WithValidator(&pipelineValidator{}).
+		Complete()
+}
+
+// pipelineValidator rejects Pipelines whose parameters CodeFresh would refuse.
+type pipelineValidator struct{}
+
+func (v *pipelineValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	p, ok := obj.(*Pipeline)
+	if !ok {
+		return nil, errors.New(errNotPipeline)
+	}
+	return nil, p.validate()
+}
+
+// ValidateUpdate validates only changes to the spec, so that a Pipeline stored
+// before the webhook existed, or under looser rules, can still have its
+// metadata and status updated and be deleted.
+func (v *pipelineValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	p, ok := newObj.(*Pipeline)
+	if !ok {
+		return nil, errors.New(errNotPipeline)
+	}
+	old, ok := oldObj.(*Pipeline)
+	if !ok {
+		return nil, errors.New(errNotPipeline)
+	}
+	if p.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(old.Spec, p.Spec) {
+		return nil, nil
+	}
+	return nil, p.validate()
+}
+
+func (v *pipelineValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (p *Pipeline) validate() error {
+	errs := validatePipelineParameters(&p.Spec.ForProvider, field.NewPath("spec", "forProvider"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(PipelineGroupVersionKind.GroupKind(), p.GetName(), errs)
+}
+
+func validatePipelineParameters(in *PipelineParameters, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if in.Metadata.Name == "" {
+		errs = append(errs, field.Required(path.Child("metadata", "name"), "pipeline name must be set"))
+	}
+
+	spec := path.Child("spec")
+	stages := make(map[string]bool, len(in.Spec.Stages))
+	for i, s := range in.Spec.Stages {
+		if stages[s] {
+			errs = append(errs, field.Duplicate(spec.Child("stages").Index(i), s))
+		}
+		stages[s] = true
+	}
+
+	// CodeFresh defaults the stages of a pipeline that declares none, and
+	// Observe late-initializes them, so steps can only be checked against
+	// stages the spec declares.
+	for name, step := range in.Spec.Steps {
+		for i, kv := range step.Values {
+			if kv.Key == stepStageKey && len(stages) > 0 && !stages[kv.Value] {
+				errs = append(errs, field.NotFound(spec.Child("steps").Key(name).Child("values").Index(i), kv.Value))
+			}
+		}
+	}
+
+	triggers := make(map[string]bool, len(in.Spec.Triggers))
+	for i, t := range in.Spec.Triggers {
+		tp := spec.Child("triggers").Index(i)
+		if triggers[t.Name] {
+			errs = append(errs, field.Duplicate(tp.Child("name"), t.Name))
+		}
+		triggers[t.Name] = true
+		errs = append(errs, validateRegex(tp.Child("branchRegex"), t.BranchRegex)...)
+		errs = append(errs, validateRegex(tp.Child("commentRegex"), t.CommentRegex)...)
+		errs = append(errs, validateVariables(tp.Child("variables"), t.Variables)...)
+	}
+
+	cronTriggers := make(map[string]bool, len(in.Spec.CronTriggers))
+	for i, t := range in.Spec.CronTriggers {
+		tp := spec.Child("cronTriggers").Index(i)
+		if cronTriggers[t.Name] {
+			errs = append(errs, field.Duplicate(tp.Child("name"), t.Name))
+		}
+		cronTriggers[t.Name] = true
+		if t.Expression == "" {
+			errs = append(errs, field.Required(tp.Child("expression"), "cron expression must be set"))
+		}
+		errs = append(errs, validateVariables(tp.Child("variables"), t.Variables)...)
+	}
+
+	errs = append(errs, validateSpecTemplate(spec.Child("specTemplate"), &in.Spec)...)
+
+	errs = append(errs, validateExternalResources(spec.Child("externalResources"), in.Spec.ExternalResources)...)
+
+	if tp := in.Spec.TerminationPolicy; tp != nil && tp.TerminatePreviousBuilds != nil {
+		errs = append(errs, validateRegex(spec.Child("terminationPolicy", "terminatePreviousBuilds", "branchName"), tp.TerminatePreviousBuilds.BranchName)...)
+	}
+
+	return append(errs, validateVariables(spec.Child("variables"), in.Spec.Variables)...)
+}
+
+func validateSpecTemplate(path *field.Path, in *PipelineSpecStruct) field.ErrorList {
+	t := in.SpecTemplate
+	if t == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if len(in.Steps) > 0 {
+		errs = append(errs, field.Forbidden(path, "specTemplate and steps are mutually exclusive"))
+	}
+	if t.Location != "" && t.Location != SpecTemplateLocationGit {
+		errs = append(errs, field.NotSupported(path.Child("location"), t.Location, []string{SpecTemplateLocationGit}))
+	}
+	errs = append(errs, validateRepo(path.Child("repo"), t.Repo)...)
+	if t.Revision == "" {
+		errs = append(errs, field.Required(path.Child("revision"), "revision must be set"))
+	}
+	return errs
+}
+
+func validateExternalResources(path *field.Path, rs []PipelineExternalResource) field.ErrorList {
+	var errs field.ErrorList
+	destinations := make(map[string]bool, len(rs))
+	for i, r := range rs {
+		rp := path.Index(i)
+		if r.Type != "" && r.Type != ExternalResourceTypeGit {
+			errs = append(errs, field.NotSupported(rp.Child("type"), r.Type, []string{ExternalResourceTypeGit}))
+		}
+		errs = append(errs, validateRepo(rp.Child("repo"), r.Repo)...)
+		if r.Revision == "" {
+			errs = append(errs, field.Required(rp.Child("revision"), "revision must be set"))
+		}
+		if r.Source == "" {
+			errs = append(errs, field.Required(rp.Child("source"), "source path must be set"))
+		}
+		if r.Destination == "" {
+			errs = append(errs, field.Required(rp.Child("destination"), "destination path must be set"))
+			continue
+		}
+		if destinations[r.Destination] {
+			errs = append(errs, field.Duplicate(rp.Child("destination"), r.Destination))
+		}
+		destinations[r.Destination] = true
+	}
+	return errs
+}
+
+func validateRepo(path *field.Path, repo string) field.ErrorList {
+	if owner, name, ok := strings.Cut(repo, "/"); repo == "" {
+		return field.ErrorList{field.Required(path, "repository must be set")}
+	} else if !ok || owner == "" || name == "" {
+		return field.ErrorList{field.Invalid(path, repo, "repository must be in the form owner/name")}
+	}
+	return nil
+}
+
+func validateVariables(path *field.Path, vars []PipelineVariable) field.ErrorList {
+	var errs field.ErrorList
+	keys := make(map[string]bool, len(vars))
+	for i, v := range vars {
+		if v.Key == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("key"), "variable key must be set"))
+			continue
+		}
+		if keys[v.Key] {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("key"), v.Key))
+		}
+		keys[v.Key] = true
+		errs = append(errs, validateVariableSource(path.Index(i), v.Value, v.ValueFrom)...)
+	}
+	return errs
+}
+
+// validateVariableSource checks that a variable reads its value from exactly
+// one Secret or ConfigMap key, if any, and does not set a value as well.
+func validateVariableSource(path *field.Path, value string, src *VariableSource) field.ErrorList {
+	if src == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if value != "" {
+		errs = append(errs, field.Forbidden(path.Child("value"), "value and valueFrom are mutually exclusive"))
+	}
+	vp := path.Child("valueFrom")
+	switch {
+	case src.SecretKeyRef == nil && src.ConfigMapKeyRef == nil:
+		errs = append(errs, field.Required(vp, "either secretKeyRef or configMapKeyRef must be set"))
+	case src.SecretKeyRef != nil && src.ConfigMapKeyRef != nil:
+		errs = append(errs, field.Forbidden(vp, "secretKeyRef and configMapKeyRef are mutually exclusive"))
+	}
+	return errs
+}
+
+// validateRegex checks that a CodeFresh trigger regex, written either plainly
+// or as a JavaScript literal such as /^dev-.*/gi, compiles. Lookarounds are
+// valid in CodeFresh but not supported by Go, so they are not reported.
+func validateRegex(path *field.Path, expr string) field.ErrorList {
+	if expr == "" {
+		return nil
+	}
+	pattern := expr
+	if strings.HasPrefix(expr, "/") {
+		end := strings.LastIndex(expr, "/")
+		if end == 0 {
+			return field.ErrorList{field.Invalid(path, expr, "regex literal is missing its closing /")}
+		}
+		pattern = expr[1:end]
+		for _, f := range expr[end+1:] {
+			switch f {
+			case 'i', 'm', 's':
+				pattern = "(?" + string(f) + ")" + pattern
+			case 'g', 'u', 'y':
+			default:
+				return field.ErrorList{field.Invalid(path, expr, fmt.Sprintf("unknown regex flag %q", f))}
+			}
+		}
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		var serr *syntax.Error
+		if errors.As(err, &serr) && serr.Code == syntax.ErrInvalidPerlOp {
+			return nil
+		}
+		return field.ErrorList{field.Invalid(path, expr, err.Error())}
+	}
+	return nil
+}
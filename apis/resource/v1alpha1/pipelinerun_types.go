@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A BuildStatus is the status of a CodeFresh build.
+type BuildStatus string
+
+// Build statuses reported by CodeFresh.
+const (
+	BuildStatusPending         BuildStatus = "pending"
+	BuildStatusElected         BuildStatus = "elected"
+	BuildStatusPendingApproval BuildStatus = "pending-approval"
+	BuildStatusRunning         BuildStatus = "running"
+	BuildStatusTerminating     BuildStatus = "terminating"
+	BuildStatusSuccess         BuildStatus = "success"
+	BuildStatusError           BuildStatus = "error"
+	BuildStatusTerminated      BuildStatus = "terminated"
+	BuildStatusDenied          BuildStatus = "denied"
+)
+
+// Terminal returns true if a build with this status has finished and will not
+// change status again.
+func (s BuildStatus) Terminal() bool {
+	switch s {
+	case BuildStatusSuccess, BuildStatusError, BuildStatusTerminated, BuildStatusDenied:
+		return true
+	}
+	return false
+}
+
+// A Build is a run of a pipeline as returned by the CodeFresh builds API.
+type Build struct {
+	ID         string      `json:"id"`
+	Status     BuildStatus `json:"status"`
+	Progress   string      `json:"progress,omitempty"`
+	PipelineID string      `json:"serviceId,omitempty"`
+	Branch     string      `json:"branchName,omitempty"`
+	Created    string      `json:"created,omitempty"`
+	Started    string      `json:"started,omitempty"`
+	Finished   string      `json:"finished,omitempty"`
+}
+
+// A BuildProgress records the steps of a build as returned by the CodeFresh
+// progress API.
+type BuildProgress struct {
+	ID     string      `json:"id"`
+	Status BuildStatus `json:"status"`
+	Steps  []BuildStep `json:"steps,omitempty"`
+}
+
+// A BuildStep is a step of a build.
+type BuildStep struct {
+	Name     string      `json:"name"`
+	Status   BuildStatus `json:"status"`
+	Started  string      `json:"creationTimeStamp,omitempty"`
+	Finished string      `json:"finishTimeStamp,omitempty"`
+	Logs     []string    `json:"logs,omitempty"`
+}
+
+// PipelineRunRequest is the body of a request to run a pipeline.
+type PipelineRunRequest struct {
+	Branch    string              `json:"branch,omitempty"`
+	Trigger   string              `json:"trigger,omitempty"`
+	Variables map[string]string   `json:"variables,omitempty"`
+	Options   *PipelineRunOptions `json:"options,omitempty"`
+}
+
+// PipelineRunOptions change how CodeFresh runs a pipeline.
+type PipelineRunOptions struct {
+	// NoCache ignores the Docker layer cache of previous builds.
+	// +optional
+	NoCache bool `json:"noCache,omitempty"`
+	// ResetVolume starts the build with an empty shared volume.
+	// +optional
+	ResetVolume bool `json:"resetVolume,omitempty"`
+}
+
+// PipelineRunParameters are the configurable fields of a PipelineRun. The
+// pipeline is run once, when the PipelineRun is created; changing the
+// parameters afterwards does not run it again.
+type PipelineRunParameters struct {
+	// PipelineID is the CodeFresh ID of the pipeline to run.
+	// +crossplane:generate:reference:type=Pipeline
+	// +crossplane:generate:reference:extractor=PipelineID()
+	// +optional
+	PipelineID *string `json:"pipelineId,omitempty"`
+
+	// PipelineIDRef references the Pipeline to run.
+	// +optional
+	PipelineIDRef *xpv1.Reference `json:"pipelineIdRef,omitempty"`
+
+	// PipelineIDSelector selects the Pipeline to run.
+	// +optional
+	PipelineIDSelector *xpv1.Selector `json:"pipelineIdSelector,omitempty"`
+
+	// Branch to build. Defaults to the branch of the selected trigger.
+	// +optional
+	Branch *string `json:"branch,omitempty"`
+
+	// Trigger is the name of the pipeline trigger whose repository and
+	// settings the build uses.
+	// +optional
+	Trigger *string `json:"trigger,omitempty"`
+
+	// Variables override the pipeline variables for this build.
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// Options change how the build is run.
+	// +optional
+	Options *PipelineRunOptions `json:"options,omitempty"`
+}
+
+// PipelineRunObservation are the observable fields of a PipelineRun.
+type PipelineRunObservation struct {
+	// BuildID is the CodeFresh ID of the build the run started.
+	BuildID string `json:"buildId,omitempty"`
+	// Status of the build.
+	Status BuildStatus `json:"status,omitempty"`
+	// Progress is the ID of the CodeFresh progress document that records
+	// the steps of the build.
+	Progress string `json:"progress,omitempty"`
+	// StartedAt is the time the build started running.
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// FinishedAt is the time the build reached a terminal status.
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+	// Duration of the build, once it finished.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Steps of the build, in the order they ran.
+	Steps []PipelineRunStep `json:"steps,omitempty"`
+	// FailedStep is the name of the step the build failed at.
+	FailedStep string `json:"failedStep,omitempty"`
+	// LogExcerpt is the tail of the log of the failed step.
+	LogExcerpt string `json:"logExcerpt,omitempty"`
+	// PlannedAction is what the provider would do in CodeFresh, such as
+	// starting the build, recorded only while the resource is in dry-run
+	// mode.
+	// +kubebuilder:validation:Enum=None;Create;Update;Delete
+	PlannedAction PlannedAction `json:"plannedAction,omitempty"`
+}
+
+// A PipelineRunStep is the observed state of a build step.
+type PipelineRunStep struct {
+	// Name of the step.
+	Name string `json:"name"`
+	// Status of the step.
+	Status BuildStatus `json:"status,omitempty"`
+	// Duration of the step, once it finished.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// A PipelineRunSpec defines the desired state of a PipelineRun.
+type PipelineRunSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       PipelineRunParameters `json:"forProvider"`
+}
+
+// A PipelineRunStatus represents the observed state of a PipelineRun.
+type PipelineRunStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PipelineRunObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PipelineRun runs a CodeFresh pipeline once and tracks the resulting build
+// until it finishes. It becomes ready only if the build succeeds. Deleting a
+// PipelineRun never stops its build: a build that is still running runs to
+// completion in CodeFresh, and remains in its build history.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="FAILED-STEP",type="string",JSONPath=".status.atProvider.failedStep",priority=1
+// +kubebuilder:printcolumn:name="BUILD",type="string",JSONPath=".status.atProvider.buildId"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,codefresh}
+type PipelineRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PipelineRunSpec   `json:"spec"`
+	Status PipelineRunStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineRunList contains a list of PipelineRun
+type PipelineRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PipelineRun `json:"items"`
+}
+
+// PipelineRun type metadata.
+var (
+	PipelineRunKind             = reflect.TypeOf(PipelineRun{}).Name()
+	PipelineRunGroupKind        = schema.GroupKind{Group: Group, Kind: PipelineRunKind}.String()
+	PipelineRunKindAPIVersion   = PipelineRunKind + "." + SchemeGroupVersion.String()
+	PipelineRunGroupVersionKind = SchemeGroupVersion.WithKind(PipelineRunKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&PipelineRun{}, &PipelineRunList{})
+}
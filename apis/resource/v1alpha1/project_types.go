@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -26,8 +27,18 @@ import (
 )
 
 type ProjectVariable struct {
-	Key   string `json:"key"`
+	Key string `json:"key"`
+	// Value of the variable. Must be empty when ValueFrom is set.
+	// +optional
 	Value string `json:"value"`
+	// ValueFrom reads the value of the variable from a Secret or ConfigMap
+	// each time the project is reconciled.
+	// +optional
+	ValueFrom *VariableSource `json:"valueFrom,omitempty"`
+	// Encrypted stores the variable encrypted in CodeFresh, which then masks
+	// its value.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
 }
 
 type ProjectMetadata struct {
@@ -49,6 +60,7 @@ type ProjectDetails struct {
 
 type ProjectCreateParams struct {
 	ProjectName      string            `json:"projectName,omitempty"`
+	ProjectImage     string            `json:"image,omitempty"`
 	ProjectTags      []string          `json:"tags,omitempty"`
 	ProjectVariables []ProjectVariable `json:"variables,omitempty"`
 }
@@ -57,20 +69,88 @@ type CreateProjectResponse struct {
 	ProjectID string `json:"id"`
 }
 
+// A NonEmptyDeletePolicy determines what happens to a CodeFresh project that
+// still contains pipelines when its managed resource is deleted.
+type NonEmptyDeletePolicy string
+
+// Policies for deleting non-empty projects.
+const (
+	// NonEmptyDeletePolicyDelete deletes the project regardless of the
+	// pipelines it contains.
+	NonEmptyDeletePolicyDelete NonEmptyDeletePolicy = "Delete"
+	// NonEmptyDeletePolicyRefuse refuses to delete the project, blocking
+	// deletion of the managed resource until the project is empty.
+	NonEmptyDeletePolicyRefuse NonEmptyDeletePolicy = "Refuse"
+	// NonEmptyDeletePolicyOrphan leaves the project in CodeFresh and lets the
+	// managed resource be deleted.
+	NonEmptyDeletePolicyOrphan NonEmptyDeletePolicy = "Orphan"
+)
+
+// TypeDeleteRefused is the type of the condition that reports the project in
+// CodeFresh was not deleted because it still contains pipelines.
+const TypeDeleteRefused xpv1.ConditionType = "DeleteRefused"
+
+// ReasonProjectNotEmpty is the reason a project was not deleted.
+const ReasonProjectNotEmpty xpv1.ConditionReason = "ProjectNotEmpty"
+
+// DeleteRefused returns a condition that indicates the project in CodeFresh
+// was not deleted because it still contains pipelines.
+func DeleteRefused() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeleteRefused,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProjectNotEmpty,
+	}
+}
+
 // ProjectParameters are the configurable fields of a Project.
 type ProjectParameters struct {
-	ConfigurableField string `json:"configurableField"`
+	// Deprecated: ConfigurableField has no effect and is not part of v1beta1.
+	// +optional
+	ConfigurableField string `json:"configurableField,omitempty"`
 	ProjectName       string `json:"projectName,omitempty"`
+	// ProjectImage is late-initialized from the image CodeFresh assigns to
+	// the project when none is given.
+	// +optional
+	ProjectImage *string `json:"projectImage,omitempty"`
 	// *optional
 	ProjectTags []string `json:"projectTags,omitempty"`
 	// *optional
 	ProjectVariables []ProjectVariable `json:"projectVariables,omitempty"`
+	// DeletePolicyForNonEmpty determines what happens when the project still
+	// contains pipelines on deletion. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Refuse;Orphan
+	// +optional
+	DeletePolicyForNonEmpty NonEmptyDeletePolicy `json:"deletePolicyForNonEmpty,omitempty"`
+	// ManagementMode determines whether the tags and variables of the
+	// project are replaced by the declared ones (Authoritative), or only the
+	// declared ones are managed, leaving others in place (Additive). Defaults
+	// to Authoritative.
+	// +kubebuilder:validation:Enum=Authoritative;Additive
+	// +optional
+	ManagementMode ManagementMode `json:"managementMode,omitempty"`
 }
 
 // ProjectObservation are the observable fields of a Project.
 type ProjectObservation struct {
 	ObservableField string `json:"observableField,omitempty"`
 	ProjectID       string `json:"projectId,omitempty"`
+	// VariableHashes are the hashes of the values last sent for encrypted
+	// variables, by key. CodeFresh masks encrypted values, so they are
+	// compared by hash instead.
+	VariableHashes map[string]string `json:"variableHashes,omitempty"`
+	// Owned are the tags and variables the provider owns in Additive
+	// management mode.
+	Owned *OwnedEntries `json:"owned,omitempty"`
+	// Drift summarizes how the project in CodeFresh differed from the spec
+	// when it was last found out of date, truncated to 1KiB. Secret values
+	// are redacted. It is cleared once the project is up to date.
+	Drift string `json:"drift,omitempty"`
+	// PlannedAction is what the provider would do to the project in CodeFresh,
+	// recorded only while the resource is in dry-run mode.
+	// +kubebuilder:validation:Enum=None;Create;Update;Delete
+	PlannedAction PlannedAction `json:"plannedAction,omitempty"`
 }
 
 // A ProjectSpec defines the desired state of a Project.
@@ -91,8 +171,10 @@ type ProjectStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="DRIFTED",type="string",JSONPath=".status.conditions[?(@.type=='Drifted')].status",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,codefresh}
 type Project struct {
 	metav1.TypeMeta   `json:",inline"`
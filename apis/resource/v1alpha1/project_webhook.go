@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const errNotProject = "object is not a Project"
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-resource-codefresh-crossplane-io-v1alpha1-project,mutating=false,failurePolicy=fail,groups=resource.codefresh.crossplane.io,resources=projects,versions=v1alpha1,name=projects.resource.codefresh.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the validating webhook of Project with
+// the supplied manager.
+func (p *Project) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(p).
+		WithValidator(&projectValidator{}).
+		Complete()
+}
+
+// projectValidator rejects Projects whose parameters CodeFresh would refuse.
+type projectValidator struct{}
+
+func (v *projectValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	p, ok := obj.(*Project)
+	if !ok {
+		return nil, errors.New(errNotProject)
+	}
+	return nil, p.validate()
+}
+
+// ValidateUpdate validates only changes to the spec, so that a Project stored
+// before the webhook existed, or under looser rules, can still have its
+// metadata and status updated and be deleted.
+func (v *projectValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	p, ok := newObj.(*Project)
+	if !ok {
+		return nil, errors.New(errNotProject)
+	}
+	old, ok := oldObj.(*Project)
+	if !ok {
+		return nil, errors.New(errNotProject)
+	}
+	if p.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(old.Spec, p.Spec) {
+		return nil, nil
+	}
+	return nil, p.validate()
+}
+
+func (v *projectValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (p *Project) validate() error {
+	errs := validateProjectParameters(&p.Spec.ForProvider, field.NewPath("spec", "forProvider"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(ProjectGroupVersionKind.GroupKind(), p.GetName(), errs)
+}
+
+func validateProjectParameters(in *ProjectParameters, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if strings.TrimSpace(in.ProjectName) == "" {
+		errs = append(errs, field.Required(path.Child("projectName"), "project name must be set"))
+	}
+
+	tags := make(map[string]bool, len(in.ProjectTags))
+	for i, t := range in.ProjectTags {
+		if tags[t] {
+			errs = append(errs, field.Duplicate(path.Child("projectTags").Index(i), t))
+		}
+		tags[t] = true
+	}
+
+	keys := make(map[string]bool, len(in.ProjectVariables))
+	for i, v := range in.ProjectVariables {
+		vp := path.Child("projectVariables").Index(i).Child("key")
+		if v.Key == "" {
+			errs = append(errs, field.Required(vp, "variable key must be set"))
+			continue
+		}
+		if keys[v.Key] {
+			errs = append(errs, field.Duplicate(vp, v.Key))
+		}
+		keys[v.Key] = true
+		errs = append(errs, validateVariableSource(path.Child("projectVariables").Index(i), v.Value, v.ValueFrom)...)
+	}
+
+	return errs
+}
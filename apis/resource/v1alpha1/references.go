@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// PipelineID extracts the CodeFresh ID of a referenced Pipeline. Its external
+// name is not used, since it is the name of the Pipeline until the pipeline
+// is created in CodeFresh, and a resolved reference is never resolved again.
+// The ID is empty until then, so the reference does not resolve.
+func PipelineID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		p, ok := mg.(*Pipeline)
+		if !ok {
+			return ""
+		}
+		return p.Status.AtProvider.ID
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPipelineRunResolveReferences(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		id      string
+		want    *string
+		wantErr bool
+	}{
+		"PipelineNotCreated": {
+			reason:  "Should not resolve the reference to the external name a Pipeline has before it is created in CodeFresh.",
+			wantErr: true,
+		},
+		"PipelineCreated": {
+			reason: "Should resolve the reference to the CodeFresh ID of the Pipeline.",
+			id:     "pipeline-id",
+			want:   pointer.String("pipeline-id"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					p := obj.(*Pipeline)
+					p.SetName(key.Name)
+					// The default initializer sets the external name to the
+					// name of the Pipeline.
+					meta.SetExternalName(p, key.Name)
+					p.Status.AtProvider.ID = tc.id
+					return nil
+				},
+			}
+			cr := &PipelineRun{Spec: PipelineRunSpec{ForProvider: PipelineRunParameters{
+				PipelineIDRef: &xpv1.Reference{Name: "pipeline"},
+			}}}
+			err := cr.ResolveReferences(context.Background(), kube)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\n%s\ncr.ResolveReferences(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, cr.Spec.ForProvider.PipelineID); diff != "" {
+				t.Errorf("\n%s\ncr.ResolveReferences(...): -want pipelineId, +got pipelineId:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
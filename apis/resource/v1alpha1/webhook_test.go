@@ -0,0 +1,350 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestValidatePipelineParameters(t *testing.T) {
+	path := field.NewPath("spec", "forProvider")
+	spec := path.Child("spec")
+
+	cases := map[string]struct {
+		reason string
+		in     PipelineParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A consistent pipeline should be accepted.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					Stages: []string{"build", "test"},
+					Steps: map[string]PipelineStep{
+						"build": {Name: "build", Values: []KeyValue{{Key: "stage", Value: "build"}}},
+					},
+					Triggers: []PipelineTrigger{
+						{Name: "push", BranchRegex: "/^((dev))-.*/gi", CommentRegex: "/^(?!skip).*/"},
+						{Name: "pr", BranchRegex: "^main$"},
+					},
+					CronTriggers: []PipelineCronTrigger{{Name: "nightly", Expression: "0 0 * * *"}},
+					Variables:    []PipelineVariable{{Key: "A", Value: "a"}},
+				},
+			},
+		},
+		"MissingName": {
+			reason: "A pipeline must be named.",
+			in:     PipelineParameters{},
+			want: field.ErrorList{
+				field.Required(path.Child("metadata", "name"), "pipeline name must be set"),
+			},
+		},
+		"StepStageMissing": {
+			reason: "A step must not reference a stage that is not declared.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					Stages: []string{"build"},
+					Steps: map[string]PipelineStep{
+						"build":  {Name: "build"},
+						"deploy": {Name: "deploy", Values: []KeyValue{{Key: "stage", Value: "deploy"}}},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.NotFound(spec.Child("steps").Key("deploy").Child("values").Index(0), "deploy"),
+			},
+		},
+		"StepStageWithoutStages": {
+			reason: "A step may reference a stage while stages are unset, since CodeFresh defaults them.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					Steps: map[string]PipelineStep{
+						"deploy": {Name: "deploy", Values: []KeyValue{{Key: "stage", Value: "deploy"}}},
+					},
+				},
+			},
+		},
+		"DuplicateNames": {
+			reason: "Trigger names and variable keys must be unique.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					Triggers:  []PipelineTrigger{{Name: "push"}, {Name: "push"}},
+					Variables: []PipelineVariable{{Key: "A"}, {Key: "A"}},
+				},
+			},
+			want: field.ErrorList{
+				field.Duplicate(spec.Child("triggers").Index(1).Child("name"), "push"),
+				field.Duplicate(spec.Child("variables").Index(1).Child("key"), "A"),
+			},
+		},
+		"VariableSource": {
+			reason: "A variable must read its value from exactly one source, and not set a value as well.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					Variables: []PipelineVariable{
+						{Key: "A", ValueFrom: &VariableSource{ConfigMapKeyRef: &ConfigMapKeySelector{Name: "cm", Namespace: "default", Key: "a"}}},
+						{Key: "B", Value: "b", ValueFrom: &VariableSource{}},
+						{Key: "C", ValueFrom: &VariableSource{
+							SecretKeyRef:    &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "s", Namespace: "default"}, Key: "c"},
+							ConfigMapKeyRef: &ConfigMapKeySelector{Name: "cm", Namespace: "default", Key: "c"},
+						}},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(spec.Child("variables").Index(1).Child("value"), "value and valueFrom are mutually exclusive"),
+				field.Required(spec.Child("variables").Index(1).Child("valueFrom"), "either secretKeyRef or configMapKeyRef must be set"),
+				field.Forbidden(spec.Child("variables").Index(2).Child("valueFrom"), "secretKeyRef and configMapKeyRef are mutually exclusive"),
+			},
+		},
+		"BadRegex": {
+			reason: "Trigger regexes must compile.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					Triggers: []PipelineTrigger{
+						{Name: "unclosed", BranchRegex: "/^dev"},
+						{Name: "flag", BranchRegex: "/^dev/x"},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Invalid(spec.Child("triggers").Index(0).Child("branchRegex"), "/^dev", "regex literal is missing its closing /"),
+				field.Invalid(spec.Child("triggers").Index(1).Child("branchRegex"), "/^dev/x", `unknown regex flag 'x'`),
+			},
+		},
+		"SpecTemplate": {
+			reason: "A spec template must locate a file in a repository and must not be combined with inline steps.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					Steps:        map[string]PipelineStep{"build": {Name: "build"}},
+					SpecTemplate: &PipelineSpecTemplate{Location: "url", Repo: "repo"},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(spec.Child("specTemplate"), "specTemplate and steps are mutually exclusive"),
+				field.NotSupported(spec.Child("specTemplate", "location"), "url", []string{"git"}),
+				field.Invalid(spec.Child("specTemplate", "repo"), "repo", "repository must be in the form owner/name"),
+				field.Required(spec.Child("specTemplate", "revision"), "revision must be set"),
+			},
+		},
+		"ExternalResources": {
+			reason: "Each external resource must locate a file in a repository and copy it to a distinct destination.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					ExternalResources: []PipelineExternalResource{
+						{Repo: "org/config", Revision: "main", Source: "ci/settings.yml", Destination: "settings.yml"},
+						{Type: "url", Repo: "config", Source: "ci/other.yml", Destination: "settings.yml"},
+						{Repo: "org/config", Revision: "main"},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.NotSupported(spec.Child("externalResources").Index(1).Child("type"), "url", []string{"git"}),
+				field.Invalid(spec.Child("externalResources").Index(1).Child("repo"), "config", "repository must be in the form owner/name"),
+				field.Required(spec.Child("externalResources").Index(1).Child("revision"), "revision must be set"),
+				field.Duplicate(spec.Child("externalResources").Index(1).Child("destination"), "settings.yml"),
+				field.Required(spec.Child("externalResources").Index(2).Child("source"), "source path must be set"),
+				field.Required(spec.Child("externalResources").Index(2).Child("destination"), "destination path must be set"),
+			},
+		},
+		"BadTerminationBranchRegex": {
+			reason: "The branch regex of the termination policy must compile.",
+			in: PipelineParameters{
+				Metadata: PipelineMetadata{Name: "project/pipeline"},
+				Spec: PipelineSpecStruct{
+					TerminationPolicy: &PipelineTerminationPolicy{
+						TerminatePreviousBuilds: &TerminatePreviousBuilds{BranchName: "/^dev"},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Invalid(spec.Child("terminationPolicy", "terminatePreviousBuilds", "branchName"), "/^dev", "regex literal is missing its closing /"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validatePipelineParameters(&tc.in, path)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidatePipelineParameters(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateRegex(t *testing.T) {
+	path := field.NewPath("regex")
+
+	cases := map[string]struct {
+		expr  string
+		valid bool
+	}{
+		"Plain":          {expr: "^main$", valid: true},
+		"Literal":        {expr: "/^((dev))-.*/gi", valid: true},
+		"Lookahead":      {expr: "/^(?!skip).*/", valid: true},
+		"Unbalanced":     {expr: "/^(dev/", valid: false},
+		"PlainMalformed": {expr: "[a-", valid: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			errs := validateRegex(path, tc.expr)
+			if got := len(errs) == 0; got != tc.valid {
+				t.Errorf("validateRegex(%q): want valid %t, got errors %v", tc.expr, tc.valid, errs)
+			}
+		})
+	}
+}
+
+func TestValidateProjectParameters(t *testing.T) {
+	path := field.NewPath("spec", "forProvider")
+
+	cases := map[string]struct {
+		reason string
+		in     ProjectParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A named project with unique tags and variables should be accepted.",
+			in: ProjectParameters{
+				ProjectName:      "project",
+				ProjectTags:      []string{"a", "b"},
+				ProjectVariables: []ProjectVariable{{Key: "A"}, {Key: "B"}},
+			},
+		},
+		"Invalid": {
+			reason: "A project must be named and have unique tags and variables.",
+			in: ProjectParameters{
+				ProjectName:      " ",
+				ProjectTags:      []string{"a", "a"},
+				ProjectVariables: []ProjectVariable{{Key: "A"}, {Key: "A"}, {}},
+			},
+			want: field.ErrorList{
+				field.Required(path.Child("projectName"), "project name must be set"),
+				field.Duplicate(path.Child("projectTags").Index(1), "a"),
+				field.Duplicate(path.Child("projectVariables").Index(1).Child("key"), "A"),
+				field.Required(path.Child("projectVariables").Index(2).Child("key"), "variable key must be set"),
+			},
+		},
+		"VariableSource": {
+			reason: "A variable must not set both a value and a source.",
+			in: ProjectParameters{
+				ProjectName: "project",
+				ProjectVariables: []ProjectVariable{
+					{Key: "A", Value: "a", ValueFrom: &VariableSource{ConfigMapKeyRef: &ConfigMapKeySelector{Name: "cm", Namespace: "default", Key: "a"}}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("projectVariables").Index(0).Child("value"), "value and valueFrom are mutually exclusive"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validateProjectParameters(&tc.in, path)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidateProjectParameters(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	now := metav1.Now()
+	finalized := metav1.ObjectMeta{Finalizers: []string{"finalizer.managedresource.crossplane.io"}}
+	deleted := metav1.ObjectMeta{DeletionTimestamp: &now}
+
+	// Both objects are invalid because they are not named.
+	invalidPipeline := func(m metav1.ObjectMeta, stage string) *Pipeline {
+		return &Pipeline{ObjectMeta: m, Spec: PipelineSpec{ForProvider: PipelineParameters{Spec: PipelineSpecStruct{Stages: []string{stage}}}}}
+	}
+	invalidProject := func(m metav1.ObjectMeta, tag string) *Project {
+		return &Project{ObjectMeta: m, Spec: ProjectSpec{ForProvider: ProjectParameters{ProjectTags: []string{tag}}}}
+	}
+
+	cases := map[string]struct {
+		reason    string
+		validator admission.CustomValidator
+		old, new  runtime.Object
+		wantErr   bool
+	}{
+		"PipelineSpecUnchanged": {
+			reason:    "An invalid Pipeline whose spec did not change should accept metadata updates, such as finalizers.",
+			validator: &pipelineValidator{},
+			old:       invalidPipeline(metav1.ObjectMeta{}, "build"),
+			new:       invalidPipeline(finalized, "build"),
+		},
+		"PipelineDeleted": {
+			reason:    "An invalid Pipeline that is being deleted should accept updates, so that its finalizer can be removed.",
+			validator: &pipelineValidator{},
+			old:       invalidPipeline(metav1.ObjectMeta{}, "build"),
+			new:       invalidPipeline(deleted, "test"),
+		},
+		"PipelineSpecChanged": {
+			reason:    "An invalid change to the spec of a Pipeline should be rejected.",
+			validator: &pipelineValidator{},
+			old:       invalidPipeline(metav1.ObjectMeta{}, "build"),
+			new:       invalidPipeline(metav1.ObjectMeta{}, "test"),
+			wantErr:   true,
+		},
+		"ProjectSpecUnchanged": {
+			reason:    "An invalid Project whose spec did not change should accept metadata updates, such as finalizers.",
+			validator: &projectValidator{},
+			old:       invalidProject(metav1.ObjectMeta{}, "a"),
+			new:       invalidProject(finalized, "a"),
+		},
+		"ProjectDeleted": {
+			reason:    "An invalid Project that is being deleted should accept updates, so that its finalizer can be removed.",
+			validator: &projectValidator{},
+			old:       invalidProject(metav1.ObjectMeta{}, "a"),
+			new:       invalidProject(deleted, "b"),
+		},
+		"ProjectSpecChanged": {
+			reason:    "An invalid change to the spec of a Project should be rejected.",
+			validator: &projectValidator{},
+			old:       invalidProject(metav1.ObjectMeta{}, "a"),
+			new:       invalidProject(metav1.ObjectMeta{}, "b"),
+			wantErr:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.validator.ValidateUpdate(context.Background(), tc.old, tc.new)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("\n%s\nValidateUpdate(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+		})
+	}
+}
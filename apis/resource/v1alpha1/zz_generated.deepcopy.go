@@ -22,9 +22,152 @@ limitations under the License.
 package v1alpha1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Annotation) DeepCopyInto(out *Annotation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Annotation.
+func (in *Annotation) DeepCopy() *Annotation {
+	if in == nil {
+		return nil
+	}
+	out := new(Annotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Build) DeepCopyInto(out *Build) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Build.
+func (in *Build) DeepCopy() *Build {
+	if in == nil {
+		return nil
+	}
+	out := new(Build)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildProgress) DeepCopyInto(out *BuildProgress) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]BuildStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildProgress.
+func (in *BuildProgress) DeepCopy() *BuildProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildStep) DeepCopyInto(out *BuildStep) {
+	*out = *in
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStep.
+func (in *BuildStep) DeepCopy() *BuildStep {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Context) DeepCopyInto(out *Context) {
+	*out = *in
+	out.Metadata = in.Metadata
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Context.
+func (in *Context) DeepCopy() *Context {
+	if in == nil {
+		return nil
+	}
+	out := new(Context)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextMetadata) DeepCopyInto(out *ContextMetadata) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextMetadata.
+func (in *ContextMetadata) DeepCopy() *ContextMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextSpec) DeepCopyInto(out *ContextSpec) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextSpec.
+func (in *ContextSpec) DeepCopy() *ContextSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CreatePipelineResponse) DeepCopyInto(out *CreatePipelineResponse) {
 	*out = *in
@@ -87,6 +230,36 @@ func (in *NullType) DeepCopy() *NullType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OwnedEntries) DeepCopyInto(out *OwnedEntries) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OwnedEntries.
+func (in *OwnedEntries) DeepCopy() *OwnedEntries {
+	if in == nil {
+		return nil
+	}
+	out := new(OwnedEntries)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pipeline) DeepCopyInto(out *Pipeline) {
 	*out = *in
@@ -132,7 +305,7 @@ func (in *PipelineContext) DeepCopy() *PipelineContext {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineCreateParams) DeepCopyInto(out *PipelineCreateParams) {
 	*out = *in
-	out.Metadata = in.Metadata
+	in.Metadata.DeepCopyInto(&out.Metadata)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
@@ -153,7 +326,9 @@ func (in *PipelineCronTrigger) DeepCopyInto(out *PipelineCronTrigger) {
 	if in.Variables != nil {
 		in, out := &in.Variables, &out.Variables
 		*out = make([]PipelineVariable, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -167,6 +342,31 @@ func (in *PipelineCronTrigger) DeepCopy() *PipelineCronTrigger {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineDeprecate) DeepCopyInto(out *PipelineDeprecate) {
+	*out = *in
+	if in.ApplicationPort != nil {
+		in, out := &in.ApplicationPort, &out.ApplicationPort
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepoPipeline != nil {
+		in, out := &in.RepoPipeline, &out.RepoPipeline
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineDeprecate.
+func (in *PipelineDeprecate) DeepCopy() *PipelineDeprecate {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineDeprecate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineDetails) DeepCopyInto(out *PipelineDetails) {
 	*out = *in
@@ -206,6 +406,21 @@ func (in *PipelineDocument) DeepCopy() *PipelineDocument {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineExternalResource) DeepCopyInto(out *PipelineExternalResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineExternalResource.
+func (in *PipelineExternalResource) DeepCopy() *PipelineExternalResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineExternalResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineList) DeepCopyInto(out *PipelineList) {
 	*out = *in
@@ -241,6 +456,33 @@ func (in *PipelineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineMetadata) DeepCopyInto(out *PipelineMetadata) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Deprecate != nil {
+		in, out := &in.Deprecate, &out.Deprecate
+		*out = new(PipelineDeprecate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineMetadata.
@@ -258,6 +500,11 @@ func (in *PipelineMetadataResponse) DeepCopyInto(out *PipelineMetadataResponse)
 	*out = *in
 	if in.Deprecate != nil {
 		in, out := &in.Deprecate, &out.Deprecate
+		*out = new(PipelineDeprecate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
 		*out = make(map[string][]string, len(*in))
 		for key, val := range *in {
 			var outVal []string
@@ -271,6 +518,97 @@ func (in *PipelineMetadataResponse) DeepCopyInto(out *PipelineMetadataResponse)
 			(*out)[key] = outVal
 		}
 	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineMetadataResponse.
+func (in *PipelineMetadataResponse) DeepCopy() *PipelineMetadataResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineMetadataResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineObservation) DeepCopyInto(out *PipelineObservation) {
+	*out = *in
+	if in.VariableHashes != nil {
+		in, out := &in.VariableHashes, &out.VariableHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TriggerVariableHashes != nil {
+		in, out := &in.TriggerVariableHashes, &out.TriggerVariableHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Owned != nil {
+		in, out := &in.Owned, &out.Owned
+		*out = new(OwnedEntries)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AnnotationKeys != nil {
+		in, out := &in.AnnotationKeys, &out.AnnotationKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineObservation.
+func (in *PipelineObservation) DeepCopy() *PipelineObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineOptions) DeepCopyInto(out *PipelineOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineOptions.
+func (in *PipelineOptions) DeepCopy() *PipelineOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineParameters) DeepCopyInto(out *PipelineParameters) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.MirrorLabels != nil {
+		in, out := &in.MirrorLabels, &out.MirrorLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineParameters.
+func (in *PipelineParameters) DeepCopy() *PipelineParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineResponsoneMetaData) DeepCopyInto(out *PipelineResponsoneMetaData) {
+	*out = *in
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string][]string, len(*in))
@@ -288,89 +626,254 @@ func (in *PipelineMetadataResponse) DeepCopyInto(out *PipelineMetadataResponse)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineMetadataResponse.
-func (in *PipelineMetadataResponse) DeepCopy() *PipelineMetadataResponse {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineResponsoneMetaData.
+func (in *PipelineResponsoneMetaData) DeepCopy() *PipelineResponsoneMetaData {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineResponsoneMetaData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineRun) DeepCopyInto(out *PipelineRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRun.
+func (in *PipelineRun) DeepCopy() *PipelineRun {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PipelineRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineRunList) DeepCopyInto(out *PipelineRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PipelineRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunList.
+func (in *PipelineRunList) DeepCopy() *PipelineRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PipelineRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineRunObservation) DeepCopyInto(out *PipelineRunObservation) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PipelineRunStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunObservation.
+func (in *PipelineRunObservation) DeepCopy() *PipelineRunObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineRunObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineRunOptions) DeepCopyInto(out *PipelineRunOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunOptions.
+func (in *PipelineRunOptions) DeepCopy() *PipelineRunOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineRunOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineRunParameters) DeepCopyInto(out *PipelineRunParameters) {
+	*out = *in
+	if in.PipelineID != nil {
+		in, out := &in.PipelineID, &out.PipelineID
+		*out = new(string)
+		**out = **in
+	}
+	if in.PipelineIDRef != nil {
+		in, out := &in.PipelineIDRef, &out.PipelineIDRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PipelineIDSelector != nil {
+		in, out := &in.PipelineIDSelector, &out.PipelineIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Branch != nil {
+		in, out := &in.Branch, &out.Branch
+		*out = new(string)
+		**out = **in
+	}
+	if in.Trigger != nil {
+		in, out := &in.Trigger, &out.Trigger
+		*out = new(string)
+		**out = **in
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(PipelineRunOptions)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunParameters.
+func (in *PipelineRunParameters) DeepCopy() *PipelineRunParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineMetadataResponse)
+	out := new(PipelineRunParameters)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineObservation) DeepCopyInto(out *PipelineObservation) {
+func (in *PipelineRunRequest) DeepCopyInto(out *PipelineRunRequest) {
 	*out = *in
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(PipelineRunOptions)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineObservation.
-func (in *PipelineObservation) DeepCopy() *PipelineObservation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunRequest.
+func (in *PipelineRunRequest) DeepCopy() *PipelineRunRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineObservation)
+	out := new(PipelineRunRequest)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineOptions) DeepCopyInto(out *PipelineOptions) {
+func (in *PipelineRunSpec) DeepCopyInto(out *PipelineRunSpec) {
 	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineOptions.
-func (in *PipelineOptions) DeepCopy() *PipelineOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunSpec.
+func (in *PipelineRunSpec) DeepCopy() *PipelineRunSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineOptions)
+	out := new(PipelineRunSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineParameters) DeepCopyInto(out *PipelineParameters) {
+func (in *PipelineRunStatus) DeepCopyInto(out *PipelineRunStatus) {
 	*out = *in
-	out.Metadata = in.Metadata
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineParameters.
-func (in *PipelineParameters) DeepCopy() *PipelineParameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunStatus.
+func (in *PipelineRunStatus) DeepCopy() *PipelineRunStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineParameters)
+	out := new(PipelineRunStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineResponsoneMetaData) DeepCopyInto(out *PipelineResponsoneMetaData) {
+func (in *PipelineRunStep) DeepCopyInto(out *PipelineRunStep) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineResponsoneMetaData.
-func (in *PipelineResponsoneMetaData) DeepCopy() *PipelineResponsoneMetaData {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunStep.
+func (in *PipelineRunStep) DeepCopy() *PipelineRunStep {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineResponsoneMetaData)
+	out := new(PipelineRunStep)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -392,22 +895,37 @@ func (in *PipelineSpec) DeepCopy() *PipelineSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineSpecRequest) DeepCopyInto(out *PipelineSpecRequest) {
+	*out = *in
+	in.PipelineSpecStruct.DeepCopyInto(&out.PipelineSpecStruct)
+	if in.OrderedSteps != nil {
+		in, out := &in.OrderedSteps, &out.OrderedSteps
+		*out = make([]PipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpecRequest.
+func (in *PipelineSpecRequest) DeepCopy() *PipelineSpecRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineSpecRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineSpecResponse) DeepCopyInto(out *PipelineSpecResponse) {
 	*out = *in
 	if in.Triggers != nil {
 		in, out := &in.Triggers, &out.Triggers
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
+		*out = make([]PipelineTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Stages != nil {
@@ -417,69 +935,61 @@ func (in *PipelineSpecResponse) DeepCopyInto(out *PipelineSpecResponse) {
 	}
 	if in.Variables != nil {
 		in, out := &in.Variables, &out.Variables
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
+		*out = make([]PipelineVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(PipelineOptions)
+		**out = **in
+	}
 	if in.Contexts != nil {
 		in, out := &in.Contexts, &out.Contexts
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SpecTemplate != nil {
+		in, out := &in.SpecTemplate, &out.SpecTemplate
+		*out = new(PipelineSpecTemplate)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int)
+		**out = **in
+	}
+	if in.TriggerConcurrency != nil {
+		in, out := &in.TriggerConcurrency, &out.TriggerConcurrency
+		*out = new(int)
+		**out = **in
+	}
+	if in.BranchConcurrency != nil {
+		in, out := &in.BranchConcurrency, &out.BranchConcurrency
+		*out = new(int)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
 	}
 	if in.TerminationPolicy != nil {
 		in, out := &in.TerminationPolicy, &out.TerminationPolicy
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+		*out = make([]PipelineTerminationRule, len(*in))
+		copy(*out, *in)
 	}
 	if in.ExternalResources != nil {
 		in, out := &in.ExternalResources, &out.ExternalResources
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+		*out = make([]PipelineExternalResource, len(*in))
+		copy(*out, *in)
 	}
 	if in.Steps != nil {
 		in, out := &in.Steps, &out.Steps
-		*out = make(map[string]PipelineStepResponse, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
+		*out = make(PipelineStepsResponse, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
@@ -518,6 +1028,11 @@ func (in *PipelineSpecStruct) DeepCopyInto(out *PipelineSpecStruct) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.SpecTemplate != nil {
+		in, out := &in.SpecTemplate, &out.SpecTemplate
+		*out = new(PipelineSpecTemplate)
+		**out = **in
+	}
 	if in.Stages != nil {
 		in, out := &in.Stages, &out.Stages
 		*out = make([]string, len(*in))
@@ -526,9 +1041,45 @@ func (in *PipelineSpecStruct) DeepCopyInto(out *PipelineSpecStruct) {
 	if in.Variables != nil {
 		in, out := &in.Variables, &out.Variables
 		*out = make([]PipelineVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(PipelineOptions)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int)
+		**out = **in
+	}
+	if in.TriggerConcurrency != nil {
+		in, out := &in.TriggerConcurrency, &out.TriggerConcurrency
+		*out = new(int)
+		**out = **in
+	}
+	if in.BranchConcurrency != nil {
+		in, out := &in.BranchConcurrency, &out.BranchConcurrency
+		*out = new(int)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
+	}
+	if in.TerminationPolicy != nil {
+		in, out := &in.TerminationPolicy, &out.TerminationPolicy
+		*out = new(PipelineTerminationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalResources != nil {
+		in, out := &in.ExternalResources, &out.ExternalResources
+		*out = make([]PipelineExternalResource, len(*in))
 		copy(*out, *in)
 	}
-	out.Options = in.Options
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpecStruct.
@@ -541,11 +1092,26 @@ func (in *PipelineSpecStruct) DeepCopy() *PipelineSpecStruct {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineSpecTemplate) DeepCopyInto(out *PipelineSpecTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpecTemplate.
+func (in *PipelineSpecTemplate) DeepCopy() *PipelineSpecTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineSpecTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStatus.
@@ -596,6 +1162,11 @@ func (in *PipelineStepResponse) DeepCopyInto(out *PipelineStepResponse) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]KeyValue, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStepResponse.
@@ -608,6 +1179,62 @@ func (in *PipelineStepResponse) DeepCopy() *PipelineStepResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PipelineStepsResponse) DeepCopyInto(out *PipelineStepsResponse) {
+	{
+		in := &in
+		*out = make(PipelineStepsResponse, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStepsResponse.
+func (in PipelineStepsResponse) DeepCopy() PipelineStepsResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStepsResponse)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineTerminationPolicy) DeepCopyInto(out *PipelineTerminationPolicy) {
+	*out = *in
+	if in.TerminatePreviousBuilds != nil {
+		in, out := &in.TerminatePreviousBuilds, &out.TerminatePreviousBuilds
+		*out = new(TerminatePreviousBuilds)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTerminationPolicy.
+func (in *PipelineTerminationPolicy) DeepCopy() *PipelineTerminationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineTerminationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineTerminationRule) DeepCopyInto(out *PipelineTerminationRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTerminationRule.
+func (in *PipelineTerminationRule) DeepCopy() *PipelineTerminationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineTerminationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineTrigger) DeepCopyInto(out *PipelineTrigger) {
 	*out = *in
@@ -625,7 +1252,9 @@ func (in *PipelineTrigger) DeepCopyInto(out *PipelineTrigger) {
 	if in.Variables != nil {
 		in, out := &in.Variables, &out.Variables
 		*out = make([]PipelineVariable, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -642,6 +1271,11 @@ func (in *PipelineTrigger) DeepCopy() *PipelineTrigger {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineVariable) DeepCopyInto(out *PipelineVariable) {
 	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(VariableSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineVariable.
@@ -692,7 +1326,9 @@ func (in *ProjectCreateParams) DeepCopyInto(out *ProjectCreateParams) {
 	if in.ProjectVariables != nil {
 		in, out := &in.ProjectVariables, &out.ProjectVariables
 		*out = make([]ProjectVariable, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -718,7 +1354,9 @@ func (in *ProjectDetails) DeepCopyInto(out *ProjectDetails) {
 	if in.ProjectVariables != nil {
 		in, out := &in.ProjectVariables, &out.ProjectVariables
 		*out = make([]ProjectVariable, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -782,6 +1420,18 @@ func (in *ProjectMetadata) DeepCopy() *ProjectMetadata {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectObservation) DeepCopyInto(out *ProjectObservation) {
 	*out = *in
+	if in.VariableHashes != nil {
+		in, out := &in.VariableHashes, &out.VariableHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Owned != nil {
+		in, out := &in.Owned, &out.Owned
+		*out = new(OwnedEntries)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectObservation.
@@ -797,6 +1447,11 @@ func (in *ProjectObservation) DeepCopy() *ProjectObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectParameters) DeepCopyInto(out *ProjectParameters) {
 	*out = *in
+	if in.ProjectImage != nil {
+		in, out := &in.ProjectImage, &out.ProjectImage
+		*out = new(string)
+		**out = **in
+	}
 	if in.ProjectTags != nil {
 		in, out := &in.ProjectTags, &out.ProjectTags
 		*out = make([]string, len(*in))
@@ -805,7 +1460,9 @@ func (in *ProjectParameters) DeepCopyInto(out *ProjectParameters) {
 	if in.ProjectVariables != nil {
 		in, out := &in.ProjectVariables, &out.ProjectVariables
 		*out = make([]ProjectVariable, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -840,7 +1497,7 @@ func (in *ProjectSpec) DeepCopy() *ProjectSpec {
 func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
@@ -856,6 +1513,11 @@ func (in *ProjectStatus) DeepCopy() *ProjectStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectVariable) DeepCopyInto(out *ProjectVariable) {
 	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(VariableSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectVariable.
@@ -867,3 +1529,43 @@ func (in *ProjectVariable) DeepCopy() *ProjectVariable {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TerminatePreviousBuilds) DeepCopyInto(out *TerminatePreviousBuilds) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerminatePreviousBuilds.
+func (in *TerminatePreviousBuilds) DeepCopy() *TerminatePreviousBuilds {
+	if in == nil {
+		return nil
+	}
+	out := new(TerminatePreviousBuilds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VariableSource) DeepCopyInto(out *VariableSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ConfigMapKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariableSource.
+func (in *VariableSource) DeepCopy() *VariableSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VariableSource)
+	in.DeepCopyInto(out)
+	return out
+}
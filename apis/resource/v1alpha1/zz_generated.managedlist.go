@@ -28,6 +28,15 @@ func (l *PipelineList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this PipelineRunList.
+func (l *PipelineRunList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this ProjectList.
 func (l *ProjectList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
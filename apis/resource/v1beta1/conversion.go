@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+const (
+	errNotPipelineHub = "hub is not a v1alpha1 Pipeline"
+	errNotProjectHub  = "hub is not a v1alpha1 Project"
+)
+
+// AnnotationKeyProjectFields keeps the fields of a v1alpha1 Project that
+// v1beta1 does not have, as a JSON object, so that they survive a round trip
+// through v1beta1.
+const AnnotationKeyProjectFields = "resource.codefresh.crossplane.io/v1alpha1-fields"
+
+// projectFields are the fields of a v1alpha1 Project that v1beta1 does not
+// have.
+type projectFields struct {
+	ConfigurableField string `json:"configurableField,omitempty"`
+	ObservableField   string `json:"observableField,omitempty"`
+}
+
+// ConvertTo converts this Pipeline to the v1alpha1 hub.
+func (src *Pipeline) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Pipeline)
+	if !ok {
+		return errors.New(errNotPipelineHub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.ResourceSpec = *src.Spec.ResourceSpec.DeepCopy()
+	dst.Status.ResourceStatus = *src.Status.ResourceStatus.DeepCopy()
+
+	in := src.Spec.ForProvider.DeepCopy()
+	dst.Spec.ForProvider = v1alpha1.PipelineParameters{
+		Metadata: v1alpha1.PipelineMetadata{
+			Name:        in.Metadata.Name,
+			Labels:      in.Metadata.Labels,
+			Annotations: in.Metadata.Annotations,
+			Deprecate:   in.Metadata.Deprecate,
+		},
+		Spec: v1alpha1.PipelineSpecStruct{
+			Triggers:     in.Spec.Triggers,
+			CronTriggers: in.Spec.CronTriggers,
+			Stages:       in.Spec.Stages,
+			Variables:    in.Spec.Variables,
+			Options:      in.Spec.Options,
+
+			SpecTemplate:       in.Spec.SpecTemplate,
+			Concurrency:        in.Spec.Concurrency,
+			TriggerConcurrency: in.Spec.TriggerConcurrency,
+			BranchConcurrency:  in.Spec.BranchConcurrency,
+			Priority:           in.Spec.Priority,
+			TerminationPolicy:  in.Spec.TerminationPolicy,
+			ExternalResources:  in.Spec.ExternalResources,
+		},
+		MirrorLabels:   in.MirrorLabels,
+		ManagementMode: in.ManagementMode,
+	}
+
+	// The hub keeps steps in a map, so their order is recorded alongside.
+	order := make([]string, 0, len(in.Spec.Steps))
+	if in.Spec.Steps != nil {
+		dst.Spec.ForProvider.Spec.Steps = make(map[string]v1alpha1.PipelineStep, len(in.Spec.Steps))
+	}
+	for _, st := range in.Spec.Steps {
+		order = append(order, st.Name)
+		dst.Spec.ForProvider.Spec.Steps[st.Name] = v1alpha1.PipelineStep{Name: st.Name, Values: st.Values}
+	}
+	dst.SetStepOrder(order)
+
+	dst.Status.AtProvider = v1alpha1.PipelineObservation{
+		ID:                    src.Status.AtProvider.ID,
+		Version:               src.Status.AtProvider.Version,
+		Kind:                  src.Status.AtProvider.Kind,
+		VariableHashes:        src.Status.AtProvider.VariableHashes,
+		TriggerVariableHashes: src.Status.AtProvider.TriggerVariableHashes,
+		Owned:                 src.Status.AtProvider.Owned.DeepCopy(),
+		Drift:                 src.Status.AtProvider.Drift,
+		Revision:              src.Status.AtProvider.Revision,
+		AppliedRevision:       src.Status.AtProvider.AppliedRevision,
+		ConflictGeneration:    src.Status.AtProvider.ConflictGeneration,
+		AnnotationKeys:        src.Status.AtProvider.AnnotationKeys,
+		PlannedAction:         src.Status.AtProvider.PlannedAction,
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to this Pipeline.
+func (dst *Pipeline) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Pipeline)
+	if !ok {
+		return errors.New(errNotPipelineHub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.ResourceSpec = *src.Spec.ResourceSpec.DeepCopy()
+	dst.Status.ResourceStatus = *src.Status.ResourceStatus.DeepCopy()
+
+	in := src.Spec.ForProvider.DeepCopy()
+	dst.Spec.ForProvider = PipelineParameters{
+		Metadata: PipelineMetadata{
+			Name:        in.Metadata.Name,
+			Labels:      in.Metadata.Labels,
+			Annotations: in.Metadata.Annotations,
+			Deprecate:   in.Metadata.Deprecate,
+		},
+		Spec: PipelineSpecStruct{
+			Triggers:     in.Spec.Triggers,
+			CronTriggers: in.Spec.CronTriggers,
+			Stages:       in.Spec.Stages,
+			Variables:    in.Spec.Variables,
+			Options:      in.Spec.Options,
+
+			SpecTemplate:       in.Spec.SpecTemplate,
+			Concurrency:        in.Spec.Concurrency,
+			TriggerConcurrency: in.Spec.TriggerConcurrency,
+			BranchConcurrency:  in.Spec.BranchConcurrency,
+			Priority:           in.Spec.Priority,
+			TerminationPolicy:  in.Spec.TerminationPolicy,
+			ExternalResources:  in.Spec.ExternalResources,
+		},
+		MirrorLabels:   in.MirrorLabels,
+		ManagementMode: in.ManagementMode,
+	}
+
+	steps := v1alpha1.SortSteps(in.Spec.Steps, src.GetStepOrder())
+	delete(dst.GetAnnotations(), v1alpha1.AnnotationKeyStepOrder)
+	for _, st := range steps {
+		dst.Spec.ForProvider.Spec.Steps = append(dst.Spec.ForProvider.Spec.Steps, PipelineStep{Name: st.Name, Values: st.Values})
+	}
+
+	dst.Status.AtProvider = PipelineObservation{
+		ID:                    src.Status.AtProvider.ID,
+		Version:               src.Status.AtProvider.Version,
+		Kind:                  src.Status.AtProvider.Kind,
+		VariableHashes:        src.Status.AtProvider.VariableHashes,
+		TriggerVariableHashes: src.Status.AtProvider.TriggerVariableHashes,
+		Owned:                 src.Status.AtProvider.Owned.DeepCopy(),
+		Drift:                 src.Status.AtProvider.Drift,
+		Revision:              src.Status.AtProvider.Revision,
+		AppliedRevision:       src.Status.AtProvider.AppliedRevision,
+		ConflictGeneration:    src.Status.AtProvider.ConflictGeneration,
+		AnnotationKeys:        src.Status.AtProvider.AnnotationKeys,
+		PlannedAction:         src.Status.AtProvider.PlannedAction,
+	}
+	return nil
+}
+
+// ConvertTo converts this Project to the v1alpha1 hub.
+func (src *Project) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Project)
+	if !ok {
+		return errors.New(errNotProjectHub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.ResourceSpec = *src.Spec.ResourceSpec.DeepCopy()
+	dst.Status.ResourceStatus = *src.Status.ResourceStatus.DeepCopy()
+
+	in := src.Spec.ForProvider.DeepCopy()
+	dst.Spec.ForProvider = v1alpha1.ProjectParameters{
+		ProjectName:             in.Name,
+		ProjectImage:            in.Image,
+		ProjectTags:             in.Tags,
+		ProjectVariables:        in.Variables,
+		DeletePolicyForNonEmpty: in.DeletePolicyForNonEmpty,
+		ManagementMode:          in.ManagementMode,
+	}
+	dst.Status.AtProvider = v1alpha1.ProjectObservation{
+		ProjectID:      src.Status.AtProvider.ID,
+		VariableHashes: src.Status.AtProvider.VariableHashes,
+		Owned:          src.Status.AtProvider.Owned.DeepCopy(),
+		Drift:          src.Status.AtProvider.Drift,
+		PlannedAction:  src.Status.AtProvider.PlannedAction,
+	}
+
+	// Fields this version does not have are restored from their annotation.
+	var f projectFields
+	_ = json.Unmarshal([]byte(dst.GetAnnotations()[AnnotationKeyProjectFields]), &f)
+	dst.Spec.ForProvider.ConfigurableField = f.ConfigurableField
+	dst.Status.AtProvider.ObservableField = f.ObservableField
+	delete(dst.GetAnnotations(), AnnotationKeyProjectFields)
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to this Project.
+func (dst *Project) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Project)
+	if !ok {
+		return errors.New(errNotProjectHub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.ResourceSpec = *src.Spec.ResourceSpec.DeepCopy()
+	dst.Status.ResourceStatus = *src.Status.ResourceStatus.DeepCopy()
+
+	in := src.Spec.ForProvider.DeepCopy()
+	dst.Spec.ForProvider = ProjectParameters{
+		Name:                    in.ProjectName,
+		Image:                   in.ProjectImage,
+		Tags:                    in.ProjectTags,
+		Variables:               in.ProjectVariables,
+		DeletePolicyForNonEmpty: in.DeletePolicyForNonEmpty,
+		ManagementMode:          in.ManagementMode,
+	}
+	dst.Status.AtProvider = ProjectObservation{
+		ID:             src.Status.AtProvider.ProjectID,
+		VariableHashes: src.Status.AtProvider.VariableHashes,
+		Owned:          src.Status.AtProvider.Owned.DeepCopy(),
+		Drift:          src.Status.AtProvider.Drift,
+		PlannedAction:  src.Status.AtProvider.PlannedAction,
+	}
+
+	// Fields this version does not have are kept in an annotation.
+	f := projectFields{ConfigurableField: in.ConfigurableField, ObservableField: src.Status.AtProvider.ObservableField}
+	if f == (projectFields{}) {
+		delete(dst.GetAnnotations(), AnnotationKeyProjectFields)
+		return nil
+	}
+	b, _ := json.Marshal(f)
+	meta.AddAnnotations(dst, map[string]string{AnnotationKeyProjectFields: string(b)})
+	return nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+func TestPipelineRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     *Pipeline
+	}{
+		"OrderedSteps": {
+			reason: "Steps should keep their order through the v1alpha1 hub.",
+			in: &Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Annotations: map[string]string{"a": "b"}},
+				Spec: PipelineSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "codefresh"}},
+					ForProvider: PipelineParameters{
+						Metadata: PipelineMetadata{Name: "project/pipeline"},
+						Spec: PipelineSpecStruct{
+							Steps: []PipelineStep{
+								{Name: "test", Values: []v1alpha1.KeyValue{{Key: "stage", Value: "test"}}},
+								{Name: "build", Values: []v1alpha1.KeyValue{{Key: "stage", Value: "build"}}},
+								{Name: "another"},
+							},
+							Stages:  []string{"build", "test"},
+							Options: &v1alpha1.PipelineOptions{NoCache: true},
+						},
+					},
+				},
+				Status: PipelineStatus{AtProvider: PipelineObservation{ID: "id", Drift: "spec.priority: want 1, got 2", Revision: 3, AppliedRevision: 2, ConflictGeneration: 4, TriggerVariableHashes: map[string]string{"push/TOKEN": "hash"}, AnnotationKeys: []string{"team"}, PlannedAction: v1alpha1.PlannedActionUpdate}},
+			},
+		},
+		"LabelsAndAnnotations": {
+			reason: "CodeFresh labels, annotations and mirrored label keys should round trip unchanged.",
+			in: &Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Labels: map[string]string{"team": "platform"}},
+				Spec: PipelineSpec{
+					ForProvider: PipelineParameters{
+						Metadata: PipelineMetadata{
+							Name:        "project/pipeline",
+							Labels:      map[string][]string{"tags": {"a", "b"}},
+							Annotations: map[string]string{"owner": "platform"},
+						},
+						MirrorLabels: []string{"team"},
+					},
+				},
+			},
+		},
+		"Deprecate": {
+			reason: "Deprecated settings should round trip unchanged.",
+			in: &Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline"},
+				Spec: PipelineSpec{
+					ForProvider: PipelineParameters{
+						Metadata: PipelineMetadata{
+							Name:      "project/pipeline",
+							Deprecate: &v1alpha1.PipelineDeprecate{ApplicationPort: pointer.String("8080"), RepoPipeline: pointer.Bool(true)},
+						},
+					},
+				},
+			},
+		},
+		"SpecTemplate": {
+			reason: "A spec template should round trip unchanged.",
+			in: &Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline"},
+				Spec: PipelineSpec{
+					ForProvider: PipelineParameters{
+						Metadata: PipelineMetadata{Name: "project/pipeline"},
+						Spec: PipelineSpecStruct{
+							SpecTemplate: &v1alpha1.PipelineSpecTemplate{Location: "git", Repo: "org/app", Path: "./codefresh.yml", Revision: "main", Context: "github"},
+						},
+					},
+				},
+			},
+		},
+		"ConcurrencyAndTermination": {
+			reason: "Concurrency, priority and termination policy should round trip unchanged.",
+			in: &Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline"},
+				Spec: PipelineSpec{
+					ForProvider: PipelineParameters{
+						Metadata: PipelineMetadata{Name: "project/pipeline"},
+						Spec: PipelineSpecStruct{
+							Concurrency:        pointer.Int(2),
+							TriggerConcurrency: pointer.Int(1),
+							BranchConcurrency:  pointer.Int(1),
+							Priority:           pointer.Int(-10),
+							TerminationPolicy: &v1alpha1.PipelineTerminationPolicy{
+								TerminatePreviousBuilds: &v1alpha1.TerminatePreviousBuilds{IgnoreTrigger: true},
+								TerminateChildBuilds:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"ExternalResources": {
+			reason: "External resources should round trip unchanged and in order.",
+			in: &Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline"},
+				Spec: PipelineSpec{
+					ForProvider: PipelineParameters{
+						Metadata: PipelineMetadata{Name: "project/pipeline"},
+						Spec: PipelineSpecStruct{
+							ExternalResources: []v1alpha1.PipelineExternalResource{
+								{Type: "git", Repo: "org/config", Revision: "main", Source: "ci", Destination: "ci", IsFolder: true},
+								{Type: "git", Repo: "org/config", Revision: "v1", Source: "a.yml", Destination: "a.yml", Context: "github"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"NoSteps": {
+			reason: "A pipeline without steps should round trip unchanged.",
+			in: &Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline"},
+				Spec: PipelineSpec{
+					ForProvider: PipelineParameters{Metadata: PipelineMetadata{Name: "project/pipeline"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hub := &v1alpha1.Pipeline{}
+			if err := tc.in.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo(...): %v", err)
+			}
+			got := &Pipeline{}
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.in, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nround trip: -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPipelineConvertFromUnorderedHub(t *testing.T) {
+	hub := &v1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{v1alpha1.AnnotationKeyStepOrder: `["test","gone"]`},
+		},
+		Spec: v1alpha1.PipelineSpec{
+			ForProvider: v1alpha1.PipelineParameters{
+				Spec: v1alpha1.PipelineSpecStruct{
+					Steps: map[string]v1alpha1.PipelineStep{
+						"build":  {Name: "build"},
+						"test":   {Name: "test"},
+						"deploy": {Name: "deploy"},
+					},
+				},
+			},
+		},
+	}
+	got := &Pipeline{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom(...): %v", err)
+	}
+	want := []PipelineStep{{Name: "test"}, {Name: "build"}, {Name: "deploy"}}
+	if diff := cmp.Diff(want, got.Spec.ForProvider.Spec.Steps); diff != "" {
+		t.Errorf("recorded steps should come first, then the rest by name: -want, +got:\n%s", diff)
+	}
+}
+
+func TestProjectRoundTrip(t *testing.T) {
+	image := "https://example.com/image.png"
+	in := &Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "project"},
+		Spec: ProjectSpec{
+			ForProvider: ProjectParameters{
+				Name:                    "project",
+				Image:                   &image,
+				Tags:                    []string{"a"},
+				Variables:               []v1alpha1.ProjectVariable{{Key: "k", Value: "v", Encrypted: true}},
+				DeletePolicyForNonEmpty: v1alpha1.NonEmptyDeletePolicyRefuse,
+				ManagementMode:          v1alpha1.ManagementModeAdditive,
+			},
+		},
+		Status: ProjectStatus{AtProvider: ProjectObservation{ID: "id", VariableHashes: map[string]string{"k": "hash"}, Owned: &v1alpha1.OwnedEntries{Tags: []string{"a"}}, Drift: "projectTags: want [\"a\"], got <unset>", PlannedAction: v1alpha1.PlannedActionCreate}},
+	}
+
+	hub := &v1alpha1.Project{}
+	if err := in.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo(...): %v", err)
+	}
+	if hub.Spec.ForProvider.ProjectName != "project" || hub.Status.AtProvider.ProjectID != "id" {
+		t.Errorf("ConvertTo(...): unexpected hub %+v", hub)
+	}
+	got := &Project{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom(...): %v", err)
+	}
+	if diff := cmp.Diff(in, got); diff != "" {
+		t.Errorf("round trip: -want, +got:\n%s", diff)
+	}
+}
+
+func TestProjectHubRoundTrip(t *testing.T) {
+	in := &v1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "project"},
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{ConfigurableField: "configurable", ProjectName: "project"},
+		},
+		Status: v1alpha1.ProjectStatus{AtProvider: v1alpha1.ProjectObservation{ObservableField: "observable", ProjectID: "id"}},
+	}
+
+	spoke := &Project{}
+	if err := spoke.ConvertFrom(in); err != nil {
+		t.Fatalf("ConvertFrom(...): %v", err)
+	}
+	got := &v1alpha1.Project{}
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo(...): %v", err)
+	}
+	if diff := cmp.Diff(in, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("round trip: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// PipelineStep defines a step in a Pipeline.
+type PipelineStep struct {
+	// Name of the step, unique within the pipeline.
+	Name   string              `json:"name"`
+	Values []v1alpha1.KeyValue `json:"values,omitempty"`
+}
+
+// PipelineMetadata identifies a Pipeline in CodeFresh.
+type PipelineMetadata struct {
+	// Name of the pipeline, in the form project/pipeline.
+	Name string `json:"name"`
+	// Labels of the pipeline in CodeFresh, such as its tags. Labels are left
+	// unmanaged when unset.
+	// +optional
+	Labels map[string][]string `json:"labels,omitempty"`
+	// Annotations to set on the pipeline in CodeFresh. Annotations that are
+	// not listed are left alone.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Deprecate marks the pipeline deprecated in CodeFresh. Deprecation is
+	// left unmanaged when unset.
+	// +optional
+	Deprecate *v1alpha1.PipelineDeprecate `json:"deprecate,omitempty"`
+}
+
+// PipelineSpecStruct is the CodeFresh spec of a Pipeline.
+type PipelineSpecStruct struct {
+	Triggers     []v1alpha1.PipelineTrigger     `json:"triggers,omitempty"`
+	CronTriggers []v1alpha1.PipelineCronTrigger `json:"cronTriggers,omitempty"`
+	// Steps of the pipeline, in the order CodeFresh runs them. Mutually
+	// exclusive with SpecTemplate.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Steps []PipelineStep `json:"steps,omitempty"`
+	// SpecTemplate loads the steps of the pipeline from a YAML file in a git
+	// repository instead. Mutually exclusive with Steps.
+	// +optional
+	SpecTemplate *v1alpha1.PipelineSpecTemplate `json:"specTemplate,omitempty"`
+	// +optional
+	Stages    []string                    `json:"stages,omitempty"`
+	Variables []v1alpha1.PipelineVariable `json:"variables,omitempty"`
+	// +optional
+	Options *v1alpha1.PipelineOptions `json:"options,omitempty"`
+
+	// Concurrency is the maximum number of builds of the pipeline that run
+	// at once. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Concurrency *int `json:"concurrency,omitempty"`
+	// TriggerConcurrency is the maximum number of builds of each trigger of
+	// the pipeline that run at once. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TriggerConcurrency *int `json:"triggerConcurrency,omitempty"`
+	// BranchConcurrency is the maximum number of builds of each branch that
+	// run at once. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BranchConcurrency *int `json:"branchConcurrency,omitempty"`
+	// Priority of the builds of the pipeline; pending builds with a higher
+	// priority start first. Left unmanaged when unset.
+	// +kubebuilder:validation:Minimum=-100
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Priority *int `json:"priority,omitempty"`
+	// TerminationPolicy terminates running builds of the pipeline when
+	// another build starts or is terminated. Left unmanaged when unset.
+	// +optional
+	TerminationPolicy *v1alpha1.PipelineTerminationPolicy `json:"terminationPolicy,omitempty"`
+	// ExternalResources are files CodeFresh copies from git repositories
+	// into the shared volume before each build, in order. Left unmanaged when
+	// unset.
+	// +optional
+	ExternalResources []v1alpha1.PipelineExternalResource `json:"externalResources,omitempty"`
+}
+
+// PipelineParameters are the configurable fields of a Pipeline.
+type PipelineParameters struct {
+	Metadata PipelineMetadata   `json:"metadata"`
+	Spec     PipelineSpecStruct `json:"spec"`
+	// MirrorLabels are the keys of labels of this Pipeline to mirror into
+	// CodeFresh annotations, for example an owner team or cost center.
+	// Annotations in metadata take precedence over mirrored labels.
+	// +optional
+	MirrorLabels []string `json:"mirrorLabels,omitempty"`
+	// ManagementMode determines whether the tags, variables and triggers of
+	// the pipeline are replaced by the declared ones (Authoritative), or only
+	// the declared ones are managed, leaving others in place (Additive).
+	// Defaults to Authoritative.
+	// +kubebuilder:validation:Enum=Authoritative;Additive
+	// +optional
+	ManagementMode v1alpha1.ManagementMode `json:"managementMode,omitempty"`
+}
+
+// PipelineObservation are the observable fields of a Pipeline.
+type PipelineObservation struct {
+	ID      string `json:"id,omitempty"`
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	// VariableHashes are the hashes of the values last sent for encrypted
+	// variables, by key. CodeFresh masks encrypted values, so they are
+	// compared by hash instead.
+	// +optional
+	VariableHashes map[string]string `json:"variableHashes,omitempty"`
+	// TriggerVariableHashes are the hashes of the values last sent for
+	// encrypted variables of the triggers, by trigger name and key joined
+	// with a slash.
+	// +optional
+	TriggerVariableHashes map[string]string `json:"triggerVariableHashes,omitempty"`
+	// Owned are the tags, variables and triggers the provider owns in
+	// Additive management mode.
+	// +optional
+	Owned *v1alpha1.OwnedEntries `json:"owned,omitempty"`
+	// Revision is the revision of the pipeline in CodeFresh that the
+	// provider last observed.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// AppliedRevision is the revision of the pipeline in CodeFresh that the
+	// provider last created or updated. Updates are made against it, so that
+	// a change made elsewhere since is not overwritten.
+	// +optional
+	AppliedRevision int `json:"appliedRevision,omitempty"`
+	// ConflictGeneration is the generation of the pipeline when an update
+	// was last rejected because the pipeline changed elsewhere. The change is
+	// overwritten once the generation differs, or its revision is accepted
+	// with the accept-revision annotation. It is zero once an update
+	// succeeds.
+	// +optional
+	ConflictGeneration int64 `json:"conflictGeneration,omitempty"`
+	// AnnotationKeys are the keys of the CodeFresh annotations of the
+	// pipeline that the provider manages. An annotation whose key is no
+	// longer desired, for example because its mirrored label was removed, is
+	// deleted.
+	// +optional
+	AnnotationKeys []string `json:"annotationKeys,omitempty"`
+	// Drift summarizes how the pipeline in CodeFresh differed from the spec
+	// when it was last found out of date, truncated to 1KiB. Secret values
+	// are redacted. It is cleared once the pipeline is up to date.
+	// +optional
+	Drift string `json:"drift,omitempty"`
+	// PlannedAction is what the provider would do to the pipeline in CodeFresh,
+	// recorded only while the resource is in dry-run mode.
+	// +kubebuilder:validation:Enum=None;Create;Update;Delete
+	// +optional
+	PlannedAction v1alpha1.PlannedAction `json:"plannedAction,omitempty"`
+}
+
+// A PipelineSpec defines the desired state of a Pipeline.
+type PipelineSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       PipelineParameters `json:"forProvider"`
+}
+
+// A PipelineStatus represents the observed state of a Pipeline.
+type PipelineStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PipelineObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Pipeline is a managed resource that represents a CodeFresh Pipeline.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="DEPRECATED",type="string",JSONPath=".status.conditions[?(@.type=='Deprecated')].status",priority=1
+// +kubebuilder:printcolumn:name="DRIFTED",type="string",JSONPath=".status.conditions[?(@.type=='Drifted')].status",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,codefresh}
+type Pipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PipelineSpec   `json:"spec"`
+	Status PipelineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineList contains a list of Pipeline
+type PipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Pipeline `json:"items"`
+}
+
+// Pipeline type metadata.
+var (
+	PipelineKind             = reflect.TypeOf(Pipeline{}).Name()
+	PipelineGroupKind        = schema.GroupKind{Group: Group, Kind: PipelineKind}.String()
+	PipelineKindAPIVersion   = PipelineKind + "." + SchemeGroupVersion.String()
+	PipelineGroupVersionKind = SchemeGroupVersion.WithKind(PipelineKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Pipeline{}, &PipelineList{})
+}
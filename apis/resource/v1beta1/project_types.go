@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// ProjectParameters are the configurable fields of a Project.
+type ProjectParameters struct {
+	// Name of the project in CodeFresh.
+	Name string `json:"name"`
+	// Image is late-initialized from the image CodeFresh assigns to the
+	// project when none is given.
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// +optional
+	Variables []v1alpha1.ProjectVariable `json:"variables,omitempty"`
+	// DeletePolicyForNonEmpty determines what happens when the project still
+	// contains pipelines on deletion. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Refuse;Orphan
+	// +optional
+	DeletePolicyForNonEmpty v1alpha1.NonEmptyDeletePolicy `json:"deletePolicyForNonEmpty,omitempty"`
+	// ManagementMode determines whether the tags and variables of the
+	// project are replaced by the declared ones (Authoritative), or only the
+	// declared ones are managed, leaving others in place (Additive). Defaults
+	// to Authoritative.
+	// +kubebuilder:validation:Enum=Authoritative;Additive
+	// +optional
+	ManagementMode v1alpha1.ManagementMode `json:"managementMode,omitempty"`
+}
+
+// ProjectObservation are the observable fields of a Project.
+type ProjectObservation struct {
+	ID string `json:"id,omitempty"`
+	// VariableHashes are the hashes of the values last sent for encrypted
+	// variables, by key. CodeFresh masks encrypted values, so they are
+	// compared by hash instead.
+	// +optional
+	VariableHashes map[string]string `json:"variableHashes,omitempty"`
+	// Owned are the tags and variables the provider owns in Additive
+	// management mode.
+	// +optional
+	Owned *v1alpha1.OwnedEntries `json:"owned,omitempty"`
+	// Drift summarizes how the project in CodeFresh differed from the spec
+	// when it was last found out of date, truncated to 1KiB. Secret values
+	// are redacted. It is cleared once the project is up to date.
+	// +optional
+	Drift string `json:"drift,omitempty"`
+	// PlannedAction is what the provider would do to the project in CodeFresh,
+	// recorded only while the resource is in dry-run mode.
+	// +kubebuilder:validation:Enum=None;Create;Update;Delete
+	// +optional
+	PlannedAction v1alpha1.PlannedAction `json:"plannedAction,omitempty"`
+}
+
+// A ProjectSpec defines the desired state of a Project.
+type ProjectSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ProjectParameters `json:"forProvider"`
+}
+
+// A ProjectStatus represents the observed state of a Project.
+type ProjectStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ProjectObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Project is a managed resource that represents a CodeFresh Project.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="DRIFTED",type="string",JSONPath=".status.conditions[?(@.type=='Drifted')].status",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,codefresh}
+type Project struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectSpec   `json:"spec"`
+	Status ProjectStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectList contains a list of Project
+type ProjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Project `json:"items"`
+}
+
+// Project type metadata.
+var (
+	ProjectKind             = reflect.TypeOf(Project{}).Name()
+	ProjectGroupKind        = schema.GroupKind{Group: Group, Kind: ProjectKind}.String()
+	ProjectKindAPIVersion   = ProjectKind + "." + SchemeGroupVersion.String()
+	ProjectGroupVersionKind = SchemeGroupVersion.WithKind(ProjectKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Project{}, &ProjectList{})
+}
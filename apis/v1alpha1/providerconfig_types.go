@@ -29,6 +29,10 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// Endpoint of the CodeFresh API. Defaults to https://g.codefresh.io/api.
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
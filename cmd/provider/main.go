@@ -28,6 +28,7 @@ import (
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -37,6 +38,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"crossplane-provider-codefresh/apis"
+	resourcev1alpha1 "crossplane-provider-codefresh/apis/resource/v1alpha1"
 	"crossplane-provider-codefresh/apis/v1alpha1"
 	codefresh "crossplane-provider-codefresh/internal/controller"
 	"crossplane-provider-codefresh/internal/features"
@@ -55,6 +57,9 @@ func main() {
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("false").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
+		dryRun                     = app.Flag("dry-run", "Record the changes that would be made to resources in CodeFresh without making them.").Default("false").Envar("DRY_RUN").Bool()
+
+		webhookTLSCertDir = app.Flag("webhook-tls-cert-dir", "The directory of TLS certificate that will be used by the webhook server. There should be tls.crt and tls.key files. Webhooks are disabled when not set.").Envar("WEBHOOK_TLS_CERT_DIR").String()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -85,6 +90,10 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		WebhookServer: webhook.NewServer(webhook.Options{
+			CertDir: *webhookTLSCertDir,
+		}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add CodeFresh APIs to scheme")
@@ -121,6 +130,17 @@ func main() {
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaManagementPolicies)
 	}
 
+	if *dryRun {
+		o.Features.Enable(features.DryRun)
+		log.Info("Dry-run mode enabled; no changes will be made in CodeFresh", "flag", features.DryRun)
+	}
+
 	kingpin.FatalIfError(codefresh.Setup(mgr, o), "Cannot setup CodeFresh controllers")
+
+	if *webhookTLSCertDir != "" {
+		kingpin.FatalIfError((&resourcev1alpha1.Pipeline{}).SetupWebhookWithManager(mgr), "Cannot setup Pipeline webhook")
+		kingpin.FatalIfError((&resourcev1alpha1.Project{}).SetupWebhookWithManager(mgr), "Cannot setup Project webhook")
+	}
+
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
@@ -0,0 +1,61 @@
+//go:build generate
+// +build generate
+
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// crd-conversion enables webhook conversion on the supplied CRD manifests.
+// controller-gen cannot generate it; Crossplane fills in the webhook client
+// configuration when it installs the provider package.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+const conversion = `spec:
+  conversion:
+    strategy: Webhook
+    webhook:
+      conversionReviewVersions:
+      - v1
+`
+
+func main() {
+	for _, f := range os.Args[1:] {
+		if err := patch(f); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot enable webhook conversion on %s: %v\n", f, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func patch(f string) error {
+	b, err := os.ReadFile(f) //nolint:gosec // Reading generated manifests.
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(b, []byte("\n  conversion:\n")) {
+		return nil
+	}
+	if !bytes.Contains(b, []byte("\nspec:\n")) {
+		return fmt.Errorf("no spec found")
+	}
+	b = bytes.Replace(b, []byte("\nspec:\n"), []byte("\n"+conversion), 1)
+	return os.WriteFile(f, b, 0o644) //nolint:gosec // Manifests are not secret.
+}
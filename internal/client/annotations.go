@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// AnnotationsAPI manages the annotations of CodeFresh entities, such as
+// pipelines, projects and builds.
+type AnnotationsAPI interface {
+	List(ctx context.Context, entityType, entityID string) ([]v1alpha1.Annotation, error)
+	// Set creates the annotation, or replaces the value of an existing
+	// annotation with the same key.
+	Set(ctx context.Context, in v1alpha1.Annotation) error
+	Delete(ctx context.Context, entityType, entityID, key string) error
+}
+
+type annotationsClient struct {
+	api *CodeFreshAPIClient
+}
+
+func entity(entityType, entityID string) url.Values {
+	return url.Values{"entityType": {entityType}, "entityId": {entityID}}
+}
+
+// List returns the annotations of the supplied entity.
+func (a *annotationsClient) List(ctx context.Context, entityType, entityID string) ([]v1alpha1.Annotation, error) {
+	var out []v1alpha1.Annotation
+	if err := a.api.do(ctx, http.MethodGet, withQuery("/annotations", entity(entityType, entityID)), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Set creates or replaces an annotation.
+func (a *annotationsClient) Set(ctx context.Context, in v1alpha1.Annotation) error {
+	return a.api.do(ctx, http.MethodPost, "/annotations", in, nil)
+}
+
+// Delete deletes the annotation of the supplied entity with the supplied key.
+func (a *annotationsClient) Delete(ctx context.Context, entityType, entityID, key string) error {
+	v := entity(entityType, entityID)
+	v.Set("key", key)
+	return a.api.do(ctx, http.MethodDelete, withQuery("/annotations", v), nil, nil)
+}
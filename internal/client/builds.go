@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// BuildsAPI runs CodeFresh pipelines and tracks the resulting builds.
+type BuildsAPI interface {
+	// Run runs the pipeline with the supplied ID or name and returns the ID
+	// of the build it started.
+	Run(ctx context.Context, pipeline string, in v1alpha1.PipelineRunRequest) (string, error)
+	Get(ctx context.Context, id string) (*v1alpha1.Build, error)
+	// Progress returns the steps of a build, given the progress ID the build
+	// refers to.
+	Progress(ctx context.Context, id string) (*v1alpha1.BuildProgress, error)
+}
+
+type buildsClient struct {
+	api *CodeFreshAPIClient
+}
+
+// Run runs the pipeline with the supplied ID or name.
+func (b *buildsClient) Run(ctx context.Context, pipeline string, in v1alpha1.PipelineRunRequest) (string, error) {
+	var id string
+	if err := b.api.do(ctx, http.MethodPost, "/pipelines/run/"+url.PathEscape(pipeline), in, &id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Get returns the build with the supplied ID.
+func (b *buildsClient) Get(ctx context.Context, id string) (*v1alpha1.Build, error) {
+	out := &v1alpha1.Build{}
+	if err := b.api.do(ctx, http.MethodGet, "/builds/"+url.PathEscape(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Progress returns the progress document with the supplied ID.
+func (b *buildsClient) Progress(ctx context.Context, id string) (*v1alpha1.BuildProgress, error) {
+	out := &v1alpha1.BuildProgress{}
+	if err := b.api.do(ctx, http.MethodGet, "/progress/"+url.PathEscape(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
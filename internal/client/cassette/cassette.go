@@ -0,0 +1,300 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cassette records CodeFresh API interactions to a file and replays
+// them offline, so client tests can run without network access.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errReadCassette  = "cannot read cassette"
+	errParseCassette = "cannot parse cassette"
+	errWriteCassette = "cannot write cassette"
+	errReadBody      = "cannot read body"
+	errFmtNoMatch    = "cassette %s has no unused interaction for %s %s"
+)
+
+// Redacted replaces the value of scrubbed headers.
+const Redacted = "REDACTED"
+
+// A Mode determines whether a Recorder records or replays interactions.
+type Mode int
+
+// Recorder modes.
+const (
+	// ModeReplay serves responses from the cassette and never touches the
+	// network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests through the real transport and records the
+	// interactions, replacing the cassette on Stop.
+	ModeRecord
+)
+
+// A Cassette is a sequence of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// An Interaction is a request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// A Request is a recorded HTTP request.
+type Request struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Query   string              `json:"query,omitempty"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// A Response is a recorded HTTP response.
+type Response struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// An Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithTransport sets the transport requests are recorded through. Defaults to
+// http.DefaultTransport.
+func WithTransport(t http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = t
+	}
+}
+
+// WithScrubbedHeaders adds headers whose values are replaced by Redacted
+// before they are recorded. Authorization is always scrubbed.
+func WithScrubbedHeaders(h ...string) Option {
+	return func(r *Recorder) {
+		for _, k := range h {
+			r.scrub[http.CanonicalHeaderKey(k)] = true
+		}
+	}
+}
+
+// A Recorder is an http.RoundTripper that records interactions to, or
+// replays them from, a cassette file.
+//
+// Requests are matched on method, path, query and body. JSON bodies are
+// compared semantically, so key order and whitespace don't matter. Each
+// recorded interaction is replayed at most once, in recorded order, so
+// repeated identical requests may receive different responses.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrub     map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder for the cassette at path. In ModeReplay the cassette
+// must exist.
+func New(path string, mode Mode, o ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrub:     map[string]bool{"Authorization": true},
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, errReadCassette)
+	}
+	if err := json.Unmarshal(b, &r.cassette); err != nil {
+		return nil, errors.Wrap(err, errParseCassette)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// RoundTrip records or replays req.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadBody)
+	}
+	in := Request{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Query:   req.URL.RawQuery,
+		Headers: r.headers(req.Header),
+		Body:    string(body),
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, in)
+	}
+	return r.replay(req, in)
+}
+
+func (r *Recorder) record(req *http.Request, in Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadBody)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  in,
+		Response: Response{Status: resp.StatusCode, Headers: r.headers(resp.Header), Body: string(body)},
+	})
+	r.used = append(r.used, true)
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, in Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, rec := range r.cassette.Interactions {
+		if r.used[i] || !matches(rec.Request, in) {
+			continue
+		}
+		r.used[i] = true
+		h := http.Header{}
+		for k, v := range rec.Response.Headers {
+			h[k] = append([]string(nil), v...)
+		}
+		return &http.Response{
+			Status:        http.StatusText(rec.Response.Status),
+			StatusCode:    rec.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        h,
+			Body:          io.NopCloser(strings.NewReader(rec.Response.Body)),
+			ContentLength: int64(len(rec.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, errors.Errorf(errFmtNoMatch, r.path, in.Method, in.Path)
+}
+
+// Unused returns the recorded interactions that have not been replayed.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Interaction
+	for i, rec := range r.cassette.Interactions {
+		if !r.used[i] {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// Stop writes the recorded interactions to the cassette file. It does
+// nothing in ModeReplay.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, errWriteCassette)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return errors.Wrap(err, errWriteCassette)
+	}
+	return errors.Wrap(os.WriteFile(r.path, append(b, '\n'), 0o600), errWriteCassette)
+}
+
+// headers returns the recordable headers of h, with scrubbed values
+// redacted. Hop-by-hop and volatile headers are dropped so cassettes stay
+// stable across recordings.
+func (r *Recorder) headers(h http.Header) map[string][]string {
+	out := map[string][]string{}
+	for k, v := range h {
+		switch k {
+		case "Date", "Content-Length", "Connection", "Accept-Encoding", "User-Agent":
+			continue
+		}
+		if r.scrub[k] {
+			out[k] = []string{Redacted}
+			continue
+		}
+		out[k] = append([]string(nil), v...)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// readBody reads and restores *b, so it can still be read by its consumer.
+func readBody(b *io.ReadCloser) ([]byte, error) {
+	if *b == nil || *b == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(*b)
+	_ = (*b).Close()
+	*b = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
+
+func matches(rec, in Request) bool {
+	return rec.Method == in.Method && rec.Path == in.Path && rec.Query == in.Query && equalBodies(rec.Body, in.Body)
+}
+
+// equalBodies compares JSON bodies semantically and anything else verbatim.
+func equalBodies(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var ja, jb interface{}
+	if json.Unmarshal([]byte(a), &ja) != nil || json.Unmarshal([]byte(b), &jb) != nil {
+		return false
+	}
+	ca, _ := json.Marshal(ja)
+	cb, _ := json.Marshal(jb)
+	return bytes.Equal(ca, cb)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func do(t *testing.T, rt http.RoundTripper, method, url, body string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("http.NewRequest(...): %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Api-Key", "secret")
+	return rt.RoundTrip(req)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"call":`+strconv.Itoa(calls)+`,"echo":`+string(b)+`}`)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := New(path, ModeRecord, WithScrubbedHeaders("x-api-key"))
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+	for _, body := range []string{`{"a":1,"b":2}`, `{"a":1,"b":2}`, `{"a":3}`} {
+		resp, err := do(t, rec, http.MethodPost, srv.URL+"/projects?limit=1", body)
+		if err != nil {
+			t.Fatalf("record RoundTrip(...): %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop(): %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile(...): %v", err)
+	}
+	if strings.Contains(string(b), "secret") {
+		t.Errorf("cassette should not contain credentials:\n%s", b)
+	}
+
+	rep, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+	cases := []struct {
+		reason string
+		body   string
+		want   string
+	}{
+		{reason: "JSON bodies should match regardless of key order.", body: `{"b":2, "a":1}`, want: `{"call":1,"echo":{"a":1,"b":2}}`},
+		{reason: "A repeated request should get the next recorded response.", body: `{"a":1,"b":2}`, want: `{"call":2,"echo":{"a":1,"b":2}}`},
+		{reason: "A different body should match its own recording.", body: `{"a":3}`, want: `{"call":3,"echo":{"a":3}}`},
+	}
+	for _, tc := range cases {
+		resp, err := do(t, rep, http.MethodPost, "http://elsewhere/projects?limit=1", tc.body)
+		if err != nil {
+			t.Fatalf("\n%s\nreplay RoundTrip(...): %v", tc.reason, err)
+		}
+		got, _ := io.ReadAll(resp.Body)
+		if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+			t.Errorf("\n%s\nreplay RoundTrip(...): -want, +got:\n%s", tc.reason, diff)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("replay should not reach the server, got %d calls", calls)
+	}
+	if u := rep.Unused(); len(u) != 0 {
+		t.Errorf("Unused(): want none, got %+v", u)
+	}
+}
+
+func TestReplayNoMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	c := `{"interactions":[{"request":{"method":"GET","path":"/projects/a"},"response":{"status":200,"body":"{}"}}]}`
+	if err := os.WriteFile(path, []byte(c), 0o600); err != nil {
+		t.Fatalf("os.WriteFile(...): %v", err)
+	}
+	rep, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		method string
+		path   string
+		body   string
+	}{
+		"Method": {method: http.MethodDelete, path: "/projects/a"},
+		"Path":   {method: http.MethodGet, path: "/projects/b"},
+		"Query":  {method: http.MethodGet, path: "/projects/a?limit=1"},
+		"Body":   {method: http.MethodGet, path: "/projects/a", body: `{"a":1}`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := do(t, rep, tc.method, "http://codefresh"+tc.path, tc.body); err == nil {
+				t.Errorf("RoundTrip(...): want no match error")
+			}
+		})
+	}
+	if len(rep.Unused()) != 1 {
+		t.Errorf("mismatched requests should not consume the interaction")
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/pkg/errors"
@@ -19,19 +18,38 @@ const (
 	errorCreatingRequest    = "error creating request"
 	errorSendingRequest     = "error sending request to CodeFresh"
 	errorClosingBodyRequest = "Failed to close response body"
+	errorDecodingResponse   = "failed to decode response"
 )
 
 var ErrResourceNotFound = errors.New("resource not found in CodeFresh")
 
+// IsNotFound returns true if err indicates that the requested CodeFresh
+// resource does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrResourceNotFound)
+}
+
+// ErrConflict is returned when CodeFresh rejects a change because the resource
+// changed since the revision the change was made against.
+var ErrConflict = errors.New("resource changed in CodeFresh")
+
+// IsConflict returns true if err indicates that CodeFresh rejected a change
+// that conflicts with the current state of the resource.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// CodeFreshAPI is the typed CodeFresh API, grouped by resource.
 type CodeFreshAPI interface {
-	CheckResourceExists(ctx context.Context, resourceType, id string) (bool, error)
-	GetResource(ctx context.Context, resourceType, id string, response interface{}) error
-	CreateResource(ctx context.Context, resourceType string, params, response interface{}) error
-	UpdateResource(ctx context.Context, resourceType, id string, params, response interface{}) error
-	DeleteResource(ctx context.Context, resourceType, id string) error
-	// Add other methods as needed
+	Projects() ProjectsAPI
+	Pipelines() PipelinesAPI
+	Contexts() ContextsAPI
+	Builds() BuildsAPI
+	Annotations() AnnotationsAPI
 }
 
+var _ CodeFreshAPI = &CodeFreshAPIClient{}
+
 type CodeFreshAPIClient struct {
 	httpClient *http.Client
 	baseURL    string
@@ -39,13 +57,28 @@ type CodeFreshAPIClient struct {
 	logger     logging.Logger
 }
 
-func NewCodeFreshAPIClient(apiKey, baseURL string, logger logging.Logger) *CodeFreshAPIClient {
-	return &CodeFreshAPIClient{
+// A ClientOption configures a CodeFreshAPIClient.
+type ClientOption func(*CodeFreshAPIClient)
+
+// WithTransport makes the client send its requests through t, e.g. to record
+// or replay them in tests.
+func WithTransport(t http.RoundTripper) ClientOption {
+	return func(c *CodeFreshAPIClient) {
+		c.httpClient.Transport = t
+	}
+}
+
+func NewCodeFreshAPIClient(apiKey, baseURL string, logger logging.Logger, o ...ClientOption) *CodeFreshAPIClient {
+	c := &CodeFreshAPIClient{
 		httpClient: &http.Client{},
 		baseURL:    baseURL,
 		apiKey:     apiKey,
 		logger:     logger,
 	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
 }
 
 // sendRequest sends an HTTP request to the CodeFresh API and handles the response.
@@ -90,8 +123,11 @@ func (c *CodeFreshAPIClient) sendRequest(ctx context.Context, method, path strin
 		respBody, _ := io.ReadAll(resp.Body)
 
 		switch resp.StatusCode {
-		case http.StatusNotFound, http.StatusInternalServerError:
-			// Specific handling for 404 and 500 errors
+		case http.StatusNotFound:
+			return nil, errors.Wrapf(ErrResourceNotFound, "CodeFresh API returned error: %s - %s", resp.Status, string(respBody))
+		case http.StatusConflict:
+			return nil, errors.Wrapf(ErrConflict, "CodeFresh API returned error: %s - %s", resp.Status, string(respBody))
+		case http.StatusInternalServerError:
 			return nil, errors.Errorf("CodeFresh API returned error: %s - %s", resp.Status, string(respBody))
 		default:
 			// General error handling
@@ -102,88 +138,35 @@ func (c *CodeFreshAPIClient) sendRequest(ctx context.Context, method, path strin
 	return resp, nil
 }
 
-// Generic CRUD operations for different resources in CodeFresh.
-
-// CheckResourceExists checks if a resource exists in CodeFresh.
-func (c *CodeFreshAPIClient) CheckResourceExists(ctx context.Context, resourceType, id string) (bool, error) {
-	var response struct {
-		ID string `json:"id"`
-	}
-
-	resp, err := c.sendRequest(ctx, "GET", "/"+resourceType+"/"+id, nil)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "500") {
-			return false, nil
-		}
-		return false, err
-	}
-
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			c.logger.Debug(errorClosingBodyRequest)
-		}
-	}()
-
-	return response.ID != "", nil
+// Projects returns the client for CodeFresh projects.
+func (c *CodeFreshAPIClient) Projects() ProjectsAPI {
+	return &projectsClient{api: c}
 }
 
-// GetResource fetches a resource from CodeFresh.
-func (c *CodeFreshAPIClient) GetResource(ctx context.Context, resourceType, id string, response interface{}) error {
-	resp, err := c.sendRequest(ctx, "GET", "/"+resourceType+"/"+id, nil)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			c.logger.Debug(errorClosingBodyRequest)
-		}
-	}()
-
-	return json.NewDecoder(resp.Body).Decode(response)
+// Pipelines returns the client for CodeFresh pipelines.
+func (c *CodeFreshAPIClient) Pipelines() PipelinesAPI {
+	return &pipelinesClient{api: c}
 }
 
-// CreateResource creates a new resource in CodeFresh.
-func (c *CodeFreshAPIClient) CreateResource(ctx context.Context, resourceType string, params, response interface{}) error {
-	resp, err := c.sendRequest(ctx, "POST", "/"+resourceType, params)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			c.logger.Debug(errorClosingBodyRequest)
-		}
-	}()
-
-	return json.NewDecoder(resp.Body).Decode(response)
+// Contexts returns the client for CodeFresh contexts.
+func (c *CodeFreshAPIClient) Contexts() ContextsAPI {
+	return &contextsClient{api: c}
 }
 
-// UpdateResource updates an existing resource in CodeFresh.
-func (c *CodeFreshAPIClient) UpdateResource(ctx context.Context, resourceType, id string, params, response interface{}) error {
-	resp, err := c.sendRequest(ctx, "PATCH", "/"+resourceType+"/"+id, params)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			c.logger.Debug("Error closing response body", "error", err)
-		}
-	}()
-
-	// Only attempt to unmarshal if a response struct is provided
-	if response != nil {
-		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
-			return errors.Wrap(err, "failed to decode response")
-		}
-	}
+// Builds returns the client for CodeFresh builds.
+func (c *CodeFreshAPIClient) Builds() BuildsAPI {
+	return &buildsClient{api: c}
+}
 
-	return nil
+// Annotations returns the client for annotations of CodeFresh entities.
+func (c *CodeFreshAPIClient) Annotations() AnnotationsAPI {
+	return &annotationsClient{api: c}
 }
 
-// DeleteResource deletes a resource from CodeFresh.
-func (c *CodeFreshAPIClient) DeleteResource(ctx context.Context, resourceType, id string) error {
-	resp, err := c.sendRequest(ctx, "DELETE", "/"+resourceType+"/"+id, nil)
+// do sends a request to path and decodes the response into response, unless
+// it is nil.
+func (c *CodeFreshAPIClient) do(ctx context.Context, method, path string, body, response interface{}) error {
+	resp, err := c.sendRequest(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
@@ -193,10 +176,8 @@ func (c *CodeFreshAPIClient) DeleteResource(ctx context.Context, resourceType, i
 		}
 	}()
 
-	// Check the response status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return errors.Errorf("unexpected response from CodeFresh on delete: %s", resp.Status)
+	if response == nil {
+		return nil
 	}
-
-	return nil
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(response), errorDecodingResponse)
 }
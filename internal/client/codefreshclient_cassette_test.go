@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+	"crossplane-provider-codefresh/internal/client/cassette"
+	"crossplane-provider-codefresh/internal/client/fake"
+)
+
+var record = flag.Bool("record", false, "re-record the golden cassettes against the fake CodeFresh server")
+
+// replayURL is never dialled; replayed requests only match on their path.
+const replayURL = "http://codefresh.invalid"
+
+// withCassette returns a client whose requests are replayed from the named
+// golden cassette, or recorded into it when -record is set.
+func withCassette(t *testing.T, name string) *CodeFreshAPIClient {
+	t.Helper()
+	path := filepath.Join("testdata", "cassettes", name+".json")
+
+	url, mode := replayURL, cassette.ModeReplay
+	if *record {
+		srv := fake.NewServer(fake.WithAPIKey(testAPIKey))
+		t.Cleanup(srv.Close)
+		url, mode = srv.URL, cassette.ModeRecord
+	}
+
+	r, err := cassette.New(path, mode)
+	if err != nil {
+		t.Fatalf("cassette.New(...): %v", err)
+	}
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Errorf("r.Stop(): %v", err)
+		}
+		if u := r.Unused(); len(u) > 0 {
+			t.Errorf("%d recorded interactions were not replayed, first: %+v", len(u), u[0].Request)
+		}
+	})
+	return NewCodeFreshAPIClient(testAPIKey, url, logging.NewNopLogger(), WithTransport(r))
+}
+
+func TestGoldenCassettes(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		run    func(ctx context.Context, t *testing.T, c CodeFreshAPI)
+	}{
+		"projects": {
+			reason: "Projects should be created, found, listed, patched and deleted.",
+			run: func(ctx context.Context, t *testing.T, c CodeFreshAPI) {
+				created, err := c.Projects().Create(ctx, v1alpha1.ProjectCreateParams{ProjectName: "project", ProjectTags: []string{"a"}})
+				if err != nil {
+					t.Fatalf("Create(...): %v", err)
+				}
+				tags := []string{"a", "b"}
+				if _, err := c.Projects().Patch(ctx, created.ProjectID, ProjectPatch{Tags: &tags}); err != nil {
+					t.Fatalf("Patch(...): %v", err)
+				}
+				got, err := c.Projects().GetByName(ctx, "project")
+				if err != nil {
+					t.Fatalf("GetByName(...): %v", err)
+				}
+				if diff := cmp.Diff(tags, got.ProjectTags); diff != "" {
+					t.Errorf("GetByName(...): -want tags, +got tags:\n%s", diff)
+				}
+				page, err := c.Projects().List(ctx, ProjectListOptions{ListOptions: ListOptions{Limit: 10}})
+				if err != nil {
+					t.Fatalf("List(...): %v", err)
+				}
+				if page.Total != 1 || len(page.Projects) != 1 || page.Projects[0].ProjectID != created.ProjectID {
+					t.Errorf("List(...): want the created project, got %+v", page)
+				}
+				if err := c.Projects().Delete(ctx, created.ProjectID); err != nil {
+					t.Fatalf("Delete(...): %v", err)
+				}
+				if _, err := c.Projects().Get(ctx, created.ProjectID); !IsNotFound(err) {
+					t.Errorf("Get(...): want not found error, got %v", err)
+				}
+			},
+		},
+		"pipelines": {
+			reason: "Pipelines should be created in a project, found by name, patched and deleted.",
+			run: func(ctx context.Context, t *testing.T, c CodeFreshAPI) {
+				project, err := c.Projects().Create(ctx, v1alpha1.ProjectCreateParams{ProjectName: "project"})
+				if err != nil {
+					t.Fatalf("Create(...): %v", err)
+				}
+				created, err := c.Pipelines().Create(ctx, v1alpha1.PipelineCreateParams{Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"}})
+				if err != nil {
+					t.Fatalf("Create(...): %v", err)
+				}
+				got, err := c.Pipelines().GetByName(ctx, "project/pipeline")
+				if err != nil {
+					t.Fatalf("GetByName(...): %v", err)
+				}
+				if got.Metadata.ID != created.Metadata.ID || got.Metadata.ProjectId != project.ProjectID {
+					t.Errorf("GetByName(...): want pipeline %q in project %q, got %+v", created.Metadata.ID, project.ProjectID, got.Metadata)
+				}
+				if diff := cmp.Diff([]string{"clone", "build", "test"}, got.Spec.Stages); diff != "" {
+					t.Errorf("GetByName(...): -want stages, +got stages:\n%s", diff)
+				}
+				patched, err := c.Pipelines().Patch(ctx, created.Metadata.ID, PipelinePatch{
+					Metadata: PipelinePatchMetadata{Labels: map[string][]string{"tags": {"a"}}},
+				})
+				if err != nil {
+					t.Fatalf("Patch(...): %v", err)
+				}
+				if patched.Metadata.Revision != 2 || len(patched.Spec.Stages) != 3 {
+					t.Errorf("Patch(...): want revision 2 with unchanged spec, got %+v", patched)
+				}
+				list, err := c.Pipelines().List(ctx, PipelineListOptions{})
+				if err != nil {
+					t.Fatalf("List(...): %v", err)
+				}
+				if list.Count != 1 {
+					t.Errorf("List(...): want 1 pipeline, got %d", list.Count)
+				}
+				if err := c.Pipelines().Delete(ctx, created.Metadata.ID); err != nil {
+					t.Fatalf("Delete(...): %v", err)
+				}
+			},
+		},
+		"builds": {
+			reason: "Pipelines should be run and their builds and progress found.",
+			run: func(ctx context.Context, t *testing.T, c CodeFreshAPI) {
+				if _, err := c.Projects().Create(ctx, v1alpha1.ProjectCreateParams{ProjectName: "project"}); err != nil {
+					t.Fatalf("Create(...): %v", err)
+				}
+				pipeline, err := c.Pipelines().Create(ctx, v1alpha1.PipelineCreateParams{Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"}})
+				if err != nil {
+					t.Fatalf("Create(...): %v", err)
+				}
+				id, err := c.Builds().Run(ctx, pipeline.Metadata.ID, v1alpha1.PipelineRunRequest{
+					Branch:    "main",
+					Variables: map[string]string{"A": "b"},
+					Options:   &v1alpha1.PipelineRunOptions{NoCache: true},
+				})
+				if err != nil {
+					t.Fatalf("Run(...): %v", err)
+				}
+				got, err := c.Builds().Get(ctx, id)
+				if err != nil {
+					t.Fatalf("Get(...): %v", err)
+				}
+				if got.ID != id || got.Status != v1alpha1.BuildStatusPending || got.PipelineID != pipeline.Metadata.ID {
+					t.Errorf("Get(...): want pending build %q of pipeline %q, got %+v", id, pipeline.Metadata.ID, got)
+				}
+				progress, err := c.Builds().Progress(ctx, got.Progress)
+				if err != nil {
+					t.Fatalf("Progress(...): %v", err)
+				}
+				if progress.ID != got.Progress || len(progress.Steps) != 0 {
+					t.Errorf("Progress(...): want progress %q without steps, got %+v", got.Progress, progress)
+				}
+				if _, err := c.Builds().Get(ctx, "missing"); !IsNotFound(err) {
+					t.Errorf("Get(...): want not found error, got %v", err)
+				}
+			},
+		},
+		"contexts": {
+			reason: "Contexts should be created, found, listed by type and deleted by name.",
+			run: func(ctx context.Context, t *testing.T, c CodeFreshAPI) {
+				in := v1alpha1.Context{
+					Metadata: v1alpha1.ContextMetadata{Name: "git"},
+					Spec:     v1alpha1.ContextSpec{Type: "git.github", Data: map[string]string{"auth": "token"}},
+				}
+				if _, err := c.Contexts().Create(ctx, in); err != nil {
+					t.Fatalf("Create(...): %v", err)
+				}
+				got, err := c.Contexts().GetByName(ctx, "git")
+				if err != nil {
+					t.Fatalf("GetByName(...): %v", err)
+				}
+				if diff := cmp.Diff(&in, got); diff != "" {
+					t.Errorf("GetByName(...): -want, +got:\n%s", diff)
+				}
+				list, err := c.Contexts().List(ctx, "secret")
+				if err != nil {
+					t.Fatalf("List(...): %v", err)
+				}
+				if len(list) != 0 {
+					t.Errorf("List(...): want no secret contexts, got %+v", list)
+				}
+				if err := c.Contexts().Delete(ctx, "git"); err != nil {
+					t.Fatalf("Delete(...): %v", err)
+				}
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Log(tc.reason)
+			tc.run(context.Background(), t, withCassette(t, name))
+		})
+	}
+}
@@ -2,63 +2,264 @@ package client
 
 import (
 	"context"
+
 	"crossplane-provider-codefresh/apis/resource/v1alpha1"
 )
 
+// MockCodeFreshAPIClient is a CodeFreshAPI whose responses are configured per
+// resource. Unset responses are returned as empty objects.
 type MockCodeFreshAPIClient struct {
-	MockCheckResourceExistsResponse bool
-	MockCheckResourceExistsErr      error
+	MockProjects    MockProjectsClient
+	MockPipelines   MockPipelinesClient
+	MockContexts    MockContextsClient
+	MockBuilds      MockBuildsClient
+	MockAnnotations MockAnnotationsClient
+}
 
-	MockGetResourceResponse *v1alpha1.ProjectDetails
-	MockGetResourceErr      error
+var _ CodeFreshAPI = &MockCodeFreshAPIClient{}
 
-	MockCreateResourceResponse interface{}
-	MockCreateResourceErr      error
+// Projects returns the mock projects client.
+func (m *MockCodeFreshAPIClient) Projects() ProjectsAPI {
+	return &m.MockProjects
+}
 
-	MockUpdateResourceResponse interface{}
-	MockUpdateResourceErr      error
+// Pipelines returns the mock pipelines client.
+func (m *MockCodeFreshAPIClient) Pipelines() PipelinesAPI {
+	return &m.MockPipelines
+}
 
-	MockDeleteResourceErr error
+// Contexts returns the mock contexts client.
+func (m *MockCodeFreshAPIClient) Contexts() ContextsAPI {
+	return &m.MockContexts
 }
 
-var _ CodeFreshAPI = &MockCodeFreshAPIClient{}
+// Builds returns the mock builds client.
+func (m *MockCodeFreshAPIClient) Builds() BuildsAPI {
+	return &m.MockBuilds
+}
 
-// CheckResourceExists simulates checking if a resource exists in CodeFresh.
-func (m *MockCodeFreshAPIClient) CheckResourceExists(ctx context.Context, resourceType, id string) (bool, error) {
-	return m.MockCheckResourceExistsResponse, m.MockCheckResourceExistsErr
+// Annotations returns the mock annotations client.
+func (m *MockCodeFreshAPIClient) Annotations() AnnotationsAPI {
+	return &m.MockAnnotations
 }
 
-// GetResource simulates fetching a resource from CodeFresh.
-func (m *MockCodeFreshAPIClient) GetResource(ctx context.Context, resourceType, id string, response interface{}) error {
-	// Check if a mock error is set
-	if m.MockGetResourceErr != nil {
-		return m.MockGetResourceErr
-	}
+// MockProjectsClient is a mock ProjectsAPI.
+type MockProjectsClient struct {
+	MockGetResponse *v1alpha1.ProjectDetails
+	MockGetErr      error
 
-	// Cast the response to the expected type
-	switch resourceType {
-	case "projects":
-		*response.(*v1alpha1.ProjectDetails) = *m.MockGetResourceResponse
-	default:
-		return nil
-	}
+	MockGetByNameResponse *v1alpha1.ProjectDetails
+	MockGetByNameErr      error
+
+	MockListResponse *ProjectPage
+	MockListErr      error
+
+	MockCreateResponse *v1alpha1.CreateProjectResponse
+	MockCreateErr      error
+
+	MockPatchResponse *v1alpha1.ProjectDetails
+	MockPatchErr      error
+
+	MockDeleteErr error
+}
+
+var _ ProjectsAPI = &MockProjectsClient{}
+
+// Get simulates fetching a project.
+func (m *MockProjectsClient) Get(ctx context.Context, id string) (*v1alpha1.ProjectDetails, error) {
+	return orEmpty(m.MockGetResponse), m.MockGetErr
+}
+
+// GetByName simulates fetching a project by name.
+func (m *MockProjectsClient) GetByName(ctx context.Context, name string) (*v1alpha1.ProjectDetails, error) {
+	return orEmpty(m.MockGetByNameResponse), m.MockGetByNameErr
+}
+
+// List simulates listing projects.
+func (m *MockProjectsClient) List(ctx context.Context, o ProjectListOptions) (*ProjectPage, error) {
+	return orEmpty(m.MockListResponse), m.MockListErr
+}
+
+// Iterate simulates paging through projects. MockListResponse is treated as
+// the only page.
+func (m *MockProjectsClient) Iterate(o ProjectListOptions) *Iterator[v1alpha1.ProjectDetails] {
+	return IterateProjects(m.List, o)
+}
+
+// Create simulates creating a project.
+func (m *MockProjectsClient) Create(ctx context.Context, params v1alpha1.ProjectCreateParams) (*v1alpha1.CreateProjectResponse, error) {
+	return orEmpty(m.MockCreateResponse), m.MockCreateErr
+}
+
+// Patch simulates updating a project.
+func (m *MockProjectsClient) Patch(ctx context.Context, id string, patch ProjectPatch) (*v1alpha1.ProjectDetails, error) {
+	return orEmpty(m.MockPatchResponse), m.MockPatchErr
+}
+
+// Delete simulates deleting a project.
+func (m *MockProjectsClient) Delete(ctx context.Context, id string) error {
+	return m.MockDeleteErr
+}
+
+// MockPipelinesClient is a mock PipelinesAPI.
+type MockPipelinesClient struct {
+	MockGetResponse *v1alpha1.PipelineDocument
+	MockGetErr      error
+
+	MockGetByNameResponse *v1alpha1.PipelineDocument
+	MockGetByNameErr      error
+
+	MockListResponse *v1alpha1.PipelineDetails
+	MockListErr      error
+
+	MockCreateResponse *v1alpha1.CreatePipelineResponse
+	MockCreateErr      error
+
+	MockPatchResponse *v1alpha1.PipelineDocument
+	MockPatchErr      error
+
+	MockDeleteErr error
+}
+
+var _ PipelinesAPI = &MockPipelinesClient{}
+
+// Get simulates fetching a pipeline.
+func (m *MockPipelinesClient) Get(ctx context.Context, id string) (*v1alpha1.PipelineDocument, error) {
+	return orEmpty(m.MockGetResponse), m.MockGetErr
+}
+
+// GetByName simulates fetching a pipeline by name.
+func (m *MockPipelinesClient) GetByName(ctx context.Context, name string) (*v1alpha1.PipelineDocument, error) {
+	return orEmpty(m.MockGetByNameResponse), m.MockGetByNameErr
+}
 
-	return nil
+// List simulates listing pipelines.
+func (m *MockPipelinesClient) List(ctx context.Context, o PipelineListOptions) (*v1alpha1.PipelineDetails, error) {
+	return orEmpty(m.MockListResponse), m.MockListErr
 }
 
-// CreateResource simulates creating a resource in CodeFresh.
-func (m *MockCodeFreshAPIClient) CreateResource(ctx context.Context, resourceType string, params, response interface{}) error {
-	// Simulate populating the response
-	return m.MockCreateResourceErr
+// Iterate simulates paging through pipelines. MockListResponse is treated as
+// the only page.
+func (m *MockPipelinesClient) Iterate(o PipelineListOptions) *Iterator[v1alpha1.PipelineDocument] {
+	return IteratePipelines(m.List, o)
 }
 
-// UpdateResource simulates updating a resource in CodeFresh.
-func (m *MockCodeFreshAPIClient) UpdateResource(ctx context.Context, resourceType, id string, params, response interface{}) error {
-	// Simulate populating the response
-	return m.MockUpdateResourceErr
+// Create simulates creating a pipeline.
+func (m *MockPipelinesClient) Create(ctx context.Context, params v1alpha1.PipelineCreateParams) (*v1alpha1.CreatePipelineResponse, error) {
+	return orEmpty(m.MockCreateResponse), m.MockCreateErr
 }
 
-// DeleteResource simulates deleting a resource from CodeFresh.
-func (m *MockCodeFreshAPIClient) DeleteResource(ctx context.Context, resourceType, id string) error {
-	return m.MockDeleteResourceErr
+// Patch simulates updating a pipeline.
+func (m *MockPipelinesClient) Patch(ctx context.Context, id string, patch PipelinePatch) (*v1alpha1.PipelineDocument, error) {
+	return orEmpty(m.MockPatchResponse), m.MockPatchErr
+}
+
+// Delete simulates deleting a pipeline.
+func (m *MockPipelinesClient) Delete(ctx context.Context, id string) error {
+	return m.MockDeleteErr
+}
+
+// MockContextsClient is a mock ContextsAPI.
+type MockContextsClient struct {
+	MockGetByNameResponse *v1alpha1.Context
+	MockGetByNameErr      error
+
+	MockListResponse []v1alpha1.Context
+	MockListErr      error
+
+	MockCreateResponse *v1alpha1.Context
+	MockCreateErr      error
+
+	MockDeleteErr error
+}
+
+var _ ContextsAPI = &MockContextsClient{}
+
+// GetByName simulates fetching a context.
+func (m *MockContextsClient) GetByName(ctx context.Context, name string) (*v1alpha1.Context, error) {
+	return orEmpty(m.MockGetByNameResponse), m.MockGetByNameErr
+}
+
+// List simulates listing contexts.
+func (m *MockContextsClient) List(ctx context.Context, contextType string) ([]v1alpha1.Context, error) {
+	return m.MockListResponse, m.MockListErr
+}
+
+// Create simulates creating a context.
+func (m *MockContextsClient) Create(ctx context.Context, in v1alpha1.Context) (*v1alpha1.Context, error) {
+	return orEmpty(m.MockCreateResponse), m.MockCreateErr
+}
+
+// Delete simulates deleting a context.
+func (m *MockContextsClient) Delete(ctx context.Context, name string) error {
+	return m.MockDeleteErr
+}
+
+// MockBuildsClient is a mock BuildsAPI.
+type MockBuildsClient struct {
+	MockRunResponse string
+	MockRunErr      error
+
+	MockGetResponse *v1alpha1.Build
+	MockGetErr      error
+
+	MockProgressResponse *v1alpha1.BuildProgress
+	MockProgressErr      error
+}
+
+var _ BuildsAPI = &MockBuildsClient{}
+
+// Run simulates running a pipeline.
+func (m *MockBuildsClient) Run(ctx context.Context, pipeline string, in v1alpha1.PipelineRunRequest) (string, error) {
+	return m.MockRunResponse, m.MockRunErr
+}
+
+// Get simulates fetching a build.
+func (m *MockBuildsClient) Get(ctx context.Context, id string) (*v1alpha1.Build, error) {
+	return orEmpty(m.MockGetResponse), m.MockGetErr
+}
+
+// Progress simulates fetching the steps of a build.
+func (m *MockBuildsClient) Progress(ctx context.Context, id string) (*v1alpha1.BuildProgress, error) {
+	return orEmpty(m.MockProgressResponse), m.MockProgressErr
+}
+
+// MockAnnotationsClient is a mock AnnotationsAPI. It records the annotations
+// it is asked to set and delete.
+type MockAnnotationsClient struct {
+	MockListResponse []v1alpha1.Annotation
+	MockListErr      error
+
+	MockSetErr     error
+	SetAnnotations []v1alpha1.Annotation
+
+	MockDeleteErr error
+	DeletedKeys   []string
+}
+
+var _ AnnotationsAPI = &MockAnnotationsClient{}
+
+// List simulates listing the annotations of an entity.
+func (m *MockAnnotationsClient) List(ctx context.Context, entityType, entityID string) ([]v1alpha1.Annotation, error) {
+	return m.MockListResponse, m.MockListErr
+}
+
+// Set simulates setting an annotation.
+func (m *MockAnnotationsClient) Set(ctx context.Context, in v1alpha1.Annotation) error {
+	m.SetAnnotations = append(m.SetAnnotations, in)
+	return m.MockSetErr
+}
+
+// Delete simulates deleting an annotation.
+func (m *MockAnnotationsClient) Delete(ctx context.Context, entityType, entityID, key string) error {
+	m.DeletedKeys = append(m.DeletedKeys, key)
+	return m.MockDeleteErr
+}
+
+// orEmpty returns v, or a pointer to an empty T if v is nil.
+func orEmpty[T any](v *T) *T {
+	if v == nil {
+		return new(T)
+	}
+	return v
 }
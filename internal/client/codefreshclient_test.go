@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+	"crossplane-provider-codefresh/internal/client/fake"
+)
+
+const testAPIKey = "api-key"
+
+func newTestClient(t *testing.T, o ...fake.Option) (*CodeFreshAPIClient, *fake.Server) {
+	t.Helper()
+	srv := fake.NewServer(append([]fake.Option{fake.WithAPIKey(testAPIKey)}, o...)...)
+	t.Cleanup(srv.Close)
+	return NewCodeFreshAPIClient(testAPIKey, srv.URL, logging.NewNopLogger()), srv
+}
+
+func TestProjectLifecycle(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := c.Projects().Create(ctx, v1alpha1.ProjectCreateParams{ProjectName: "project", ProjectTags: []string{"a"}})
+	if err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if created.ProjectID == "" {
+		t.Fatal("Create(...): no project ID returned")
+	}
+
+	tags := []string{"a", "b"}
+	if _, err := c.Projects().Patch(ctx, created.ProjectID, ProjectPatch{Tags: &tags}); err != nil {
+		t.Fatalf("Patch(...): %v", err)
+	}
+
+	got, err := c.Projects().Get(ctx, created.ProjectID)
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	if diff := cmp.Diff(tags, got.ProjectTags); diff != "" {
+		t.Errorf("Get(...): -want tags, +got tags:\n%s", diff)
+	}
+
+	if err := c.Projects().Delete(ctx, created.ProjectID); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	if _, ok := srv.Project(created.ProjectID); ok {
+		t.Error("Delete(...): project still exists")
+	}
+	if _, err := c.Projects().Get(ctx, created.ProjectID); !IsNotFound(err) {
+		t.Errorf("Get(...): want not found error, got %v", err)
+	}
+}
+
+func TestPipelineLifecycle(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	projectID := srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	created, err := c.Pipelines().Create(ctx, v1alpha1.PipelineCreateParams{Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"}})
+	if err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if created.Metadata.ProjectId != projectID {
+		t.Errorf("Create(...): want project ID %q, got %q", projectID, created.Metadata.ProjectId)
+	}
+
+	got, err := c.Pipelines().Get(ctx, created.Metadata.ID)
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	if got.Metadata.Name != "project/pipeline" {
+		t.Errorf("Get(...): want name %q, got %q", "project/pipeline", got.Metadata.Name)
+	}
+	if p, _ := srv.Project(projectID); p.ProjectTotalPipelinesNumber != 1 {
+		t.Errorf("project should contain 1 pipeline, got %d", p.ProjectTotalPipelinesNumber)
+	}
+
+	if err := c.Pipelines().Delete(ctx, created.Metadata.ID); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	if _, err := c.Pipelines().Get(ctx, created.Metadata.ID); !IsNotFound(err) {
+		t.Errorf("Get(...): want not found error, got %v", err)
+	}
+}
+
+func TestPipelinePatchRevision(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	created, err := c.Pipelines().Create(ctx, v1alpha1.PipelineCreateParams{Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"}})
+	if err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	id, rev := created.Metadata.ID, created.Metadata.Revision
+
+	got, err := c.Pipelines().Patch(ctx, id, PipelinePatch{Metadata: PipelinePatchMetadata{Revision: &rev}})
+	if err != nil {
+		t.Fatalf("Patch(...): %v", err)
+	}
+	if got.Metadata.Revision != rev+1 {
+		t.Errorf("Patch(...): want revision %d, got %d", rev+1, got.Metadata.Revision)
+	}
+
+	// The pipeline has changed since the revision, so the patch conflicts.
+	if _, err := c.Pipelines().Patch(ctx, id, PipelinePatch{Metadata: PipelinePatchMetadata{Revision: &rev}}); !IsConflict(err) {
+		t.Errorf("Patch(...): want conflict for stale revision, got %v", err)
+	}
+
+	// A patch without a revision is applied to any revision.
+	if _, err := c.Pipelines().Patch(ctx, id, PipelinePatch{}); err != nil {
+		t.Errorf("Patch(...): %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		setup  func(*fake.Server)
+		client func(url string) *CodeFreshAPIClient
+		ctx    func() (context.Context, context.CancelFunc)
+		want   string
+	}{
+		"Unauthorized": {
+			reason: "A request with the wrong API key should fail.",
+			client: func(url string) *CodeFreshAPIClient {
+				return NewCodeFreshAPIClient("wrong", url, logging.NewNopLogger())
+			},
+			want: "401 Unauthorized",
+		},
+		"ServerError": {
+			reason: "An injected server error should be returned.",
+			setup: func(s *fake.Server) {
+				s.InjectFault(fake.Fault{PathPrefix: "/projects", Status: http.StatusInternalServerError, Times: 1})
+			},
+			want: "500 Internal Server Error",
+		},
+		"Timeout": {
+			reason: "A slow response should fail once the context expires.",
+			setup: func(s *fake.Server) {
+				s.SetLatency(time.Second)
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			want: errorSendingRequest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, srv := newTestClient(t)
+			id := srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+			if tc.setup != nil {
+				tc.setup(srv)
+			}
+			if tc.client != nil {
+				c = tc.client(srv.URL)
+			}
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tc.ctx != nil {
+				ctx, cancel = tc.ctx()
+			}
+			defer cancel()
+
+			_, err := c.Projects().Get(ctx, id)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("\n%s\nGet(...): want error containing %q, got %v", tc.reason, tc.want, err)
+			}
+			if IsNotFound(err) {
+				t.Errorf("\n%s\nGet(...): want an error other than not found, got %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestFakeServerPagination(t *testing.T) {
+	_, srv := newTestClient(t)
+	for _, n := range []string{"a", "b", "c"} {
+		srv.AddProject(v1alpha1.ProjectDetails{ProjectName: n})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/projects?limit=2&offset=2", nil)
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /projects: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // Test cleanup.
+
+	var page struct {
+		Projects []v1alpha1.ProjectDetails `json:"projects"`
+		Total    int                       `json:"total"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatalf("decoding page: %v", err)
+	}
+	if page.Total != 3 || len(page.Projects) != 1 || page.Projects[0].ProjectName != "c" {
+		t.Errorf("want the last of 3 projects, got %+v", page)
+	}
+}
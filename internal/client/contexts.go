@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// ContextsAPI manages CodeFresh contexts. Contexts are identified by name.
+type ContextsAPI interface {
+	GetByName(ctx context.Context, name string) (*v1alpha1.Context, error)
+	// List returns the contexts of the supplied type, or all contexts if it
+	// is empty.
+	List(ctx context.Context, contextType string) ([]v1alpha1.Context, error)
+	Create(ctx context.Context, in v1alpha1.Context) (*v1alpha1.Context, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type contextsClient struct {
+	api *CodeFreshAPIClient
+}
+
+// GetByName returns the context with the supplied name.
+func (c *contextsClient) GetByName(ctx context.Context, name string) (*v1alpha1.Context, error) {
+	out := &v1alpha1.Context{}
+	if err := c.api.do(ctx, http.MethodGet, "/contexts/"+url.PathEscape(name), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List returns the contexts of the supplied type.
+func (c *contextsClient) List(ctx context.Context, contextType string) ([]v1alpha1.Context, error) {
+	v := url.Values{}
+	if contextType != "" {
+		v.Set("type", contextType)
+	}
+	var out []v1alpha1.Context
+	if err := c.api.do(ctx, http.MethodGet, withQuery("/contexts", v), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Create creates a context.
+func (c *contextsClient) Create(ctx context.Context, in v1alpha1.Context) (*v1alpha1.Context, error) {
+	out := &v1alpha1.Context{}
+	if err := c.api.do(ctx, http.MethodPost, "/contexts", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the context with the supplied name.
+func (c *contextsClient) Delete(ctx context.Context, name string) error {
+	return c.api.do(ctx, http.MethodDelete, "/contexts/"+url.PathEscape(name), nil, nil)
+}
@@ -0,0 +1,973 @@
+// Package fake provides an in-process fake of the CodeFresh API, so that the
+// CodeFresh client and the controllers can be tested without network access.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+const defaultPageSize = 25
+
+// A Fault makes the Server fail matching requests with the supplied status.
+type Fault struct {
+	// Method to match, or any method if empty.
+	Method string
+	// PathPrefix to match, e.g. /pipelines.
+	PathPrefix string
+	// Status to respond with.
+	Status int
+	// Message to respond with. Defaults to the status text.
+	Message string
+	// Times the fault applies before it is cleared. Zero means indefinitely.
+	Times int
+}
+
+// A Request records a request the Server received.
+type Request struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// An Option configures a Server.
+type Option func(*Server)
+
+// WithAPIKey makes the Server reject requests not authorized with key.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithLatency delays every response by d.
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// Server is an in-process fake of the CodeFresh API backed by in-memory stores.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	apiKey    string
+	latency   time.Duration
+	faults    []*Fault
+	requests  []Request
+	nextID    int
+	projects  map[string]*v1alpha1.ProjectDetails
+	pipelines map[string]*v1alpha1.PipelineDocument
+	contexts  map[string]*v1alpha1.Context
+	builds    map[string]*v1alpha1.Build
+	progress  map[string]*v1alpha1.BuildProgress
+	// annotations are keyed by entity type and ID, then by key.
+	annotations map[string]map[string]string
+}
+
+// NewServer starts a Server. Callers must Close it when done.
+func NewServer(o ...Option) *Server {
+	s := &Server{
+		projects:  map[string]*v1alpha1.ProjectDetails{},
+		pipelines: map[string]*v1alpha1.PipelineDocument{},
+		contexts:  map[string]*v1alpha1.Context{},
+		builds:    map[string]*v1alpha1.Build{},
+		progress:  map[string]*v1alpha1.BuildProgress{},
+
+		annotations: map[string]map[string]string{},
+	}
+	for _, fn := range o {
+		fn(s)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// InjectFault makes the Server fail requests matching f.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// SetLatency delays every subsequent response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Requests returns the requests the Server received, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// AddProject stores p, assigning it an ID if it has none, and returns the ID.
+func (s *Server) AddProject(p v1alpha1.ProjectDetails) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.ProjectID == "" {
+		p.ProjectID = s.newID()
+	}
+	s.projects[p.ProjectID] = &p
+	return p.ProjectID
+}
+
+// Project returns the stored project with the supplied ID.
+func (s *Server) Project(id string) (v1alpha1.ProjectDetails, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[id]
+	if !ok {
+		return v1alpha1.ProjectDetails{}, false
+	}
+	return *p, true
+}
+
+// AddPipeline stores p, assigning it an ID if it has none, and returns the
+// ID. The pipeline is added to the project its name is prefixed with.
+func (s *Server) AddPipeline(p v1alpha1.PipelineDocument) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Metadata.ID == "" {
+		p.Metadata.ID = s.newID()
+	}
+	s.linkProject(&p)
+	s.pipelines[p.Metadata.ID] = &p
+	return p.Metadata.ID
+}
+
+// Pipeline returns the stored pipeline with the supplied ID.
+func (s *Server) Pipeline(id string) (v1alpha1.PipelineDocument, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pipelines[id]
+	if !ok {
+		return v1alpha1.PipelineDocument{}, false
+	}
+	return *p, true
+}
+
+// EditPipeline applies edit to the stored pipeline with the supplied ID as a
+// change made elsewhere, for example in the CodeFresh UI, bumping its
+// revision.
+func (s *Server) EditPipeline(id string, edit func(*v1alpha1.PipelineDocument)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pipelines[id]
+	if !ok {
+		return false
+	}
+	edit(p)
+	p.Metadata.Revision++
+	p.Metadata.UpdatedAt = now()
+	return true
+}
+
+// AddContext stores c under its name.
+func (s *Server) AddContext(c v1alpha1.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contexts[c.Metadata.Name] = &c
+}
+
+// Build returns the stored build with the supplied ID.
+func (s *Server) Build(id string) (v1alpha1.Build, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.builds[id]
+	if !ok {
+		return v1alpha1.Build{}, false
+	}
+	return *b, true
+}
+
+// SetBuildStatus moves the build with the supplied ID to status, recording
+// when it started and finished. Builds stay pending until it is called.
+func (s *Server) SetBuildStatus(id string, status v1alpha1.BuildStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.builds[id]
+	if !ok {
+		return false
+	}
+	b.Status = status
+	if b.Started == "" && status != v1alpha1.BuildStatusPending {
+		b.Started = now()
+	}
+	if status.Terminal() {
+		b.Finished = now()
+	}
+	if p, ok := s.progress[b.Progress]; ok {
+		p.Status = status
+	}
+	return true
+}
+
+// AddBuildStep appends step to the progress of the build with the supplied
+// ID.
+func (s *Server) AddBuildStep(id string, step v1alpha1.BuildStep) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.builds[id]
+	if !ok {
+		return false
+	}
+	p, ok := s.progress[b.Progress]
+	if !ok {
+		return false
+	}
+	p.Steps = append(p.Steps, step)
+	return true
+}
+
+// Annotations returns the annotations of the supplied entity.
+func (s *Server) Annotations(entityType, entityID string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := map[string]string{}
+	for k, v := range s.annotations[entityType+"/"+entityID] {
+		out[k] = v
+	}
+	return out
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%024x", s.nextID)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body := readBody(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+
+	if s.latency > 0 {
+		s.mu.Unlock()
+		select {
+		case <-time.After(s.latency):
+		case <-r.Context().Done():
+		}
+		s.mu.Lock()
+	}
+
+	if s.apiKey != "" && strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != s.apiKey {
+		writeError(w, http.StatusUnauthorized, "Authentication failed")
+		return
+	}
+
+	if f := s.fault(r); f != nil {
+		msg := f.Message
+		if msg == "" {
+			msg = http.StatusText(f.Status)
+		}
+		writeError(w, f.Status, msg)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch segments[0] {
+	case "projects":
+		s.serveProjects(w, r, segments[1:], body)
+	case "pipelines":
+		s.servePipelines(w, r, segments[1:], body)
+	case "contexts":
+		s.serveContexts(w, r, segments[1:], body)
+	case "builds":
+		s.serveBuilds(w, r, segments[1:])
+	case "progress":
+		s.serveProgress(w, r, segments[1:])
+	case "annotations":
+		s.serveAnnotations(w, r, body)
+	default:
+		writeError(w, http.StatusNotFound, "Route not found")
+	}
+}
+
+func (s *Server) fault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || !strings.HasPrefix(r.URL.Path, f.PathPrefix) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) serveProjects(w http.ResponseWriter, r *http.Request, path []string, body []byte) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listProjects(w, r)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		s.createProject(w, body)
+	case len(path) == 2 && path[0] == "name" && r.Method == http.MethodGet:
+		for _, p := range s.projects {
+			if p.ProjectName == path[1] {
+				writeJSON(w, http.StatusOK, p)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Project not found")
+	case len(path) == 1:
+		p, ok := s.projects[path[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, p)
+		case http.MethodPatch:
+			s.updateProject(w, p, body)
+		case http.MethodDelete:
+			delete(s.projects, p.ProjectID)
+			writeJSON(w, http.StatusOK, map[string]string{})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Route not found")
+	}
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	tags := r.URL.Query()["tags"]
+	all := make([]*v1alpha1.ProjectDetails, 0, len(s.projects))
+	for _, p := range s.projects {
+		if name != "" && !strings.Contains(p.ProjectName, name) {
+			continue
+		}
+		if !containsAll(p.ProjectTags, tags) {
+			continue
+		}
+		all = append(all, p)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ProjectID < all[j].ProjectID })
+
+	limit, offset := page(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"projects": all[clamp(offset, len(all)):clamp(offset+limit, len(all))],
+		"total":    len(all),
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+func (s *Server) createProject(w http.ResponseWriter, body []byte) {
+	var in v1alpha1.ProjectCreateParams
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.ProjectName == "" {
+		writeError(w, http.StatusBadRequest, "projectName is required")
+		return
+	}
+	for _, p := range s.projects {
+		if p.ProjectName == in.ProjectName {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Project with name %s already exists", in.ProjectName))
+			return
+		}
+	}
+	p := &v1alpha1.ProjectDetails{
+		ProjectID:        s.newID(),
+		ProjectName:      in.ProjectName,
+		ProjectImage:     in.ProjectImage,
+		ProjectTags:      in.ProjectTags,
+		ProjectVariables: maskProjectVariables(in.ProjectVariables),
+		UpdatedAt:        now(),
+		ProjectMetadata:  v1alpha1.ProjectMetadata{CreatedAt: now()},
+	}
+	s.projects[p.ProjectID] = p
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, p *v1alpha1.ProjectDetails, body []byte) {
+	var in struct {
+		ProjectName *string                     `json:"projectName"`
+		Image       *string                     `json:"image"`
+		Tags        *[]string                   `json:"tags"`
+		Variables   *[]v1alpha1.ProjectVariable `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.ProjectName != nil {
+		p.ProjectName = *in.ProjectName
+	}
+	if in.Image != nil {
+		p.ProjectImage = *in.Image
+	}
+	if in.Tags != nil {
+		p.ProjectTags = *in.Tags
+	}
+	if in.Variables != nil {
+		p.ProjectVariables = maskProjectVariables(*in.Variables)
+	}
+	p.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) servePipelines(w http.ResponseWriter, r *http.Request, path []string, body []byte) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			s.listPipelines(w, r)
+		case http.MethodPost:
+			s.createPipeline(w, body)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+
+	if path[0] == "run" && r.Method == http.MethodPost {
+		s.runPipeline(w, strings.Join(path[1:], "/"), body)
+		return
+	}
+
+	// Pipelines are addressed by ID or by their project/name.
+	p := s.findPipeline(strings.Join(path, "/"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, p)
+	case http.MethodPut, http.MethodPatch:
+		s.updatePipeline(w, p, body)
+	case http.MethodDelete:
+		delete(s.pipelines, p.Metadata.ID)
+		if pr, ok := s.projects[p.Metadata.ProjectId]; ok {
+			pr.ProjectTotalPipelinesNumber--
+		}
+		writeJSON(w, http.StatusOK, map[string]string{})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) findPipeline(idOrName string) *v1alpha1.PipelineDocument {
+	if p, ok := s.pipelines[idOrName]; ok {
+		return p
+	}
+	for _, p := range s.pipelines {
+		if p.Metadata.Name == idOrName {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) listPipelines(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	all := make([]v1alpha1.PipelineDocument, 0, len(s.pipelines))
+	for _, p := range s.pipelines {
+		if n := q.Get("name"); n != "" && !strings.Contains(p.Metadata.Name, n) {
+			continue
+		}
+		if id := q.Get("projectId"); id != "" && p.Metadata.ProjectId != id {
+			continue
+		}
+		if pr := q.Get("project"); pr != "" && p.Metadata.Project != pr {
+			continue
+		}
+		if !hasLabels(p.Metadata.Labels, q) {
+			continue
+		}
+		all = append(all, *p)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Metadata.ID < all[j].Metadata.ID })
+
+	limit, offset := page(r)
+	writeJSON(w, http.StatusOK, v1alpha1.PipelineDetails{
+		Docs:  all[clamp(offset, len(all)):clamp(offset+limit, len(all))],
+		Count: len(all),
+	})
+}
+
+// pipelineRequest is a pipeline as sent by the client on create and update.
+type pipelineRequest struct {
+	Metadata struct {
+		Name      string                      `json:"name"`
+		Labels    map[string][]string         `json:"labels"`
+		Deprecate *v1alpha1.PipelineDeprecate `json:"deprecate"`
+		Revision  *int                        `json:"revision"`
+	} `json:"metadata"`
+	Spec *v1alpha1.PipelineSpecResponse `json:"spec"`
+}
+
+func (s *Server) createPipeline(w http.ResponseWriter, body []byte) {
+	var in pipelineRequest
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.Metadata.Name == "" {
+		writeError(w, http.StatusBadRequest, "metadata.name is required")
+		return
+	}
+	if s.findPipeline(in.Metadata.Name) != nil {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Pipeline with name %s already exists", in.Metadata.Name))
+		return
+	}
+	p := &v1alpha1.PipelineDocument{
+		Metadata: v1alpha1.PipelineMetadataResponse{
+			ID:        s.newID(),
+			Name:      in.Metadata.Name,
+			Labels:    in.Metadata.Labels,
+			Deprecate: in.Metadata.Deprecate,
+			Revision:  1,
+			CreatedAt: now(),
+			UpdatedAt: now(),
+		},
+		Version: "1.0",
+		Kind:    "pipeline",
+	}
+	if in.Spec != nil {
+		p.Spec = *in.Spec
+	}
+	p.Spec = maskSpec(withDefaults(p.Spec))
+	if project, _, ok := strings.Cut(in.Metadata.Name, "/"); ok {
+		if !s.hasProject(project) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Project %s not found", project))
+			return
+		}
+	}
+	s.linkProject(p)
+	s.pipelines[p.Metadata.ID] = p
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) updatePipeline(w http.ResponseWriter, p *v1alpha1.PipelineDocument, body []byte) {
+	var in pipelineRequest
+	if err := json.Unmarshal(body, &in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.Metadata.Revision != nil && *in.Metadata.Revision != p.Metadata.Revision {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Pipeline %s is at revision %d, not %d", p.Metadata.Name, p.Metadata.Revision, *in.Metadata.Revision))
+		return
+	}
+	if in.Metadata.Name != "" {
+		p.Metadata.Name = in.Metadata.Name
+	}
+	if in.Metadata.Labels != nil {
+		p.Metadata.Labels = in.Metadata.Labels
+	}
+	if in.Metadata.Deprecate != nil {
+		p.Metadata.Deprecate = in.Metadata.Deprecate
+	}
+	if in.Spec != nil {
+		p.Spec = maskSpec(withDefaults(mergeSpec(p.Spec, *in.Spec, nullFields(body))))
+	}
+	p.Metadata.Revision++
+	p.Metadata.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, p)
+}
+
+// nullFields returns the spec fields of the supplied pipeline request that
+// are sent as null.
+func nullFields(body []byte) map[string]bool {
+	var in struct {
+		Spec map[string]json.RawMessage `json:"spec"`
+	}
+	_ = json.Unmarshal(body, &in)
+	nulls := map[string]bool{}
+	for k, v := range in.Spec {
+		if string(v) == "null" {
+			nulls[k] = true
+		}
+	}
+	return nulls
+}
+
+func (s *Server) hasProject(name string) bool {
+	for _, p := range s.projects {
+		if p.ProjectName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) linkProject(p *v1alpha1.PipelineDocument) {
+	project, _, ok := strings.Cut(p.Metadata.Name, "/")
+	if !ok {
+		return
+	}
+	for _, pr := range s.projects {
+		if pr.ProjectName == project {
+			p.Metadata.Project = pr.ProjectName
+			p.Metadata.ProjectId = pr.ProjectID
+			pr.ProjectTotalPipelinesNumber++
+			return
+		}
+	}
+}
+
+// withDefaults applies the defaults CodeFresh applies to a pipeline spec.
+// mergeSpec returns spec with the fields that are set in patch replaced. The
+// steps and the spec template are also cleared when nulls holds them, i.e.
+// they were sent as null.
+func mergeSpec(spec, patch v1alpha1.PipelineSpecResponse, nulls map[string]bool) v1alpha1.PipelineSpecResponse {
+	if patch.Triggers != nil {
+		spec.Triggers = patch.Triggers
+	}
+	if patch.Stages != nil {
+		spec.Stages = patch.Stages
+	}
+	if patch.Variables != nil {
+		spec.Variables = patch.Variables
+	}
+	if patch.Options != nil {
+		spec.Options = patch.Options
+	}
+	if patch.Contexts != nil {
+		spec.Contexts = patch.Contexts
+	}
+	if patch.Concurrency != nil {
+		spec.Concurrency = patch.Concurrency
+	}
+	if patch.TriggerConcurrency != nil {
+		spec.TriggerConcurrency = patch.TriggerConcurrency
+	}
+	if patch.BranchConcurrency != nil {
+		spec.BranchConcurrency = patch.BranchConcurrency
+	}
+	if patch.Priority != nil {
+		spec.Priority = patch.Priority
+	}
+	if patch.TerminationPolicy != nil {
+		spec.TerminationPolicy = patch.TerminationPolicy
+	}
+	if patch.ExternalResources != nil {
+		spec.ExternalResources = patch.ExternalResources
+	}
+	if patch.Steps != nil || nulls["steps"] {
+		spec.Steps = patch.Steps
+	}
+	if patch.SpecTemplate != nil || nulls["specTemplate"] {
+		spec.SpecTemplate = patch.SpecTemplate
+	}
+	return spec
+}
+
+func withDefaults(spec v1alpha1.PipelineSpecResponse) v1alpha1.PipelineSpecResponse {
+	if len(spec.Stages) == 0 {
+		spec.Stages = []string{"clone", "build", "test"}
+	}
+	if spec.Options == nil {
+		spec.Options = &v1alpha1.PipelineOptions{}
+	}
+	return spec
+}
+
+// MaskedValue is the value CodeFresh returns for encrypted variables.
+const MaskedValue = "*****"
+
+// maskSpec masks the values of the encrypted variables of the spec and its
+// triggers, as CodeFresh stores them.
+func maskSpec(spec v1alpha1.PipelineSpecResponse) v1alpha1.PipelineSpecResponse {
+	spec.Variables = maskVariables(spec.Variables)
+	if spec.Triggers != nil {
+		triggers := make([]v1alpha1.PipelineTrigger, len(spec.Triggers))
+		for i, t := range spec.Triggers {
+			t.Variables = maskVariables(t.Variables)
+			triggers[i] = t
+		}
+		spec.Triggers = triggers
+	}
+	return spec
+}
+
+// maskVariables masks the values of the encrypted variables, as CodeFresh
+// does.
+func maskVariables(in []v1alpha1.PipelineVariable) []v1alpha1.PipelineVariable {
+	if in == nil {
+		return nil
+	}
+	vars := make([]v1alpha1.PipelineVariable, len(in))
+	for i, v := range in {
+		if v.Encrypted {
+			v.Value = MaskedValue
+		}
+		vars[i] = v
+	}
+	return vars
+}
+
+// maskProjectVariables masks the values of the encrypted variables, as
+// CodeFresh stores them.
+func maskProjectVariables(in []v1alpha1.ProjectVariable) []v1alpha1.ProjectVariable {
+	if in == nil {
+		return nil
+	}
+	vars := make([]v1alpha1.ProjectVariable, len(in))
+	for i, v := range in {
+		if v.Encrypted {
+			v.Value = MaskedValue
+		}
+		vars[i] = v
+	}
+	return vars
+}
+
+func (s *Server) runPipeline(w http.ResponseWriter, idOrName string, body []byte) {
+	p := s.findPipeline(idOrName)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+	var in v1alpha1.PipelineRunRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &in); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if in.Trigger != "" && !hasTrigger(p, in.Trigger) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Trigger %s not found", in.Trigger))
+		return
+	}
+	b := &v1alpha1.Build{
+		ID:         s.newID(),
+		Status:     v1alpha1.BuildStatusPending,
+		PipelineID: p.Metadata.ID,
+		Branch:     in.Branch,
+		Created:    now(),
+	}
+	b.Progress = s.newID()
+	s.builds[b.ID] = b
+	s.progress[b.Progress] = &v1alpha1.BuildProgress{ID: b.Progress, Status: b.Status}
+	writeJSON(w, http.StatusOK, b.ID)
+}
+
+func hasTrigger(p *v1alpha1.PipelineDocument, name string) bool {
+	for _, t := range p.Spec.Triggers {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveBuilds(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 1 || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "Route not found")
+		return
+	}
+	b, ok := s.builds[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Build not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, b)
+}
+
+func (s *Server) serveProgress(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 1 || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "Route not found")
+		return
+	}
+	p, ok := s.progress[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Progress not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) serveAnnotations(w http.ResponseWriter, r *http.Request, body []byte) {
+	q := r.URL.Query()
+	switch r.Method {
+	case http.MethodGet:
+		entity := q.Get("entityType") + "/" + q.Get("entityId")
+		keys := make([]string, 0, len(s.annotations[entity]))
+		for k := range s.annotations[entity] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]v1alpha1.Annotation, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, v1alpha1.Annotation{EntityType: q.Get("entityType"), EntityID: q.Get("entityId"), Key: k, Value: s.annotations[entity][k]})
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		var a v1alpha1.Annotation
+		if err := json.Unmarshal(body, &a); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if a.EntityType == "" || a.EntityID == "" || a.Key == "" {
+			writeError(w, http.StatusBadRequest, "entityType, entityId and key are required")
+			return
+		}
+		entity := a.EntityType + "/" + a.EntityID
+		if s.annotations[entity] == nil {
+			s.annotations[entity] = map[string]string{}
+		}
+		s.annotations[entity][a.Key] = a.Value
+		writeJSON(w, http.StatusOK, a)
+	case http.MethodDelete:
+		delete(s.annotations[q.Get("entityType")+"/"+q.Get("entityId")], q.Get("key"))
+		writeJSON(w, http.StatusOK, map[string]string{})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) serveContexts(w http.ResponseWriter, r *http.Request, path []string, body []byte) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		names := make([]string, 0, len(s.contexts))
+		for n := range s.contexts {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		out := make([]*v1alpha1.Context, 0, len(names))
+		for _, n := range names {
+			if t := r.URL.Query().Get("type"); t != "" && s.contexts[n].Spec.Type != t {
+				continue
+			}
+			out = append(out, s.contexts[n])
+		}
+		writeJSON(w, http.StatusOK, out)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		var c v1alpha1.Context
+		if err := json.Unmarshal(body, &c); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if c.Metadata.Name == "" {
+			writeError(w, http.StatusBadRequest, "metadata.name is required")
+			return
+		}
+		if _, ok := s.contexts[c.Metadata.Name]; ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Context with name %s already exists", c.Metadata.Name))
+			return
+		}
+		s.contexts[c.Metadata.Name] = &c
+		writeJSON(w, http.StatusOK, c)
+	case len(path) == 1:
+		c, ok := s.contexts[path[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Context not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, c)
+		case http.MethodDelete:
+			delete(s.contexts, path[0])
+			writeJSON(w, http.StatusOK, map[string]string{})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Route not found")
+	}
+}
+
+// hasLabels returns true if labels contain all values of the labels.<key>
+// query parameters of q.
+func hasLabels(labels map[string][]string, q url.Values) bool {
+	for k, want := range q {
+		if l, ok := strings.CutPrefix(k, "labels."); ok && !containsAll(labels[l], want) {
+			return false
+		}
+	}
+	return true
+}
+
+// page returns the limit and offset query parameters of r.
+func page(r *http.Request) (limit, offset int) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+	offset, err = strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// clamp returns i, or n if i exceeds it.
+func clamp(i, n int) int {
+	if i > n {
+		return n
+	}
+	return i
+}
+
+func containsAll(have, want []string) bool {
+	set := make(map[string]bool, len(have))
+	for _, h := range have {
+		set[h] = true
+	}
+	for _, w := range want {
+		if !set[w] {
+			return false
+		}
+	}
+	return true
+}
+
+func readBody(r *http.Request) []byte {
+	if r.Body == nil {
+		return nil
+	}
+	defer r.Body.Close() //nolint:errcheck // Nothing to do on failure.
+	b, _ := io.ReadAll(r.Body)
+	return b
+}
+
+// errorResponse is the error body CodeFresh responds with.
+type errorResponse struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	name := strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_")) + "_ERROR"
+	writeJSON(w, status, errorResponse{Status: status, Code: strconv.Itoa(1000 + status), Name: name, Message: msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -4,10 +4,15 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 )
 
-func NewCodeFreshService(creds []byte, logger logging.Logger) (interface{}, error) {
-	logger.Info("Received raw credentials", "creds", string(creds))
+// DefaultEndpoint is the CodeFresh API used when a ProviderConfig does not
+// specify one.
+const DefaultEndpoint = "https://g.codefresh.io/api"
+
+func NewCodeFreshService(creds []byte, endpoint string, logger logging.Logger) (CodeFreshAPI, error) {
 	apiKey := string(creds)
 
-	baseURL := "https://g.codefresh.io/api"
-	return NewCodeFreshAPIClient(apiKey, baseURL, logger), nil
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return NewCodeFreshAPIClient(apiKey, endpoint, logger), nil
 }
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions select a page of a list.
+type ListOptions struct {
+	// Limit is the maximum number of items to return. The server default is
+	// used if zero.
+	Limit int
+	// Offset is the number of items to skip.
+	Offset int
+}
+
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		v.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return v
+}
+
+// withQuery appends v to path, if it is not empty.
+func withQuery(path string, v url.Values) string {
+	if len(v) == 0 {
+		return path
+	}
+	return path + "?" + v.Encode()
+}
+
+// A PageFn fetches the page selected by o. It returns the items of the page
+// and the total number of items across all pages.
+type PageFn[T any] func(ctx context.Context, o ListOptions) (items []T, total int, err error)
+
+// An Iterator pages through a list, fetching each page when the previous one
+// has been consumed. Use it like a bufio.Scanner:
+//
+//	it := c.Projects().Iterate(client.ProjectListOptions{Tags: []string{"team-a"}})
+//	for it.Next(ctx) {
+//		p := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch PageFn[T]
+	opts  ListOptions
+
+	page  []T
+	cur   T
+	total int
+	err   error
+	done  bool
+}
+
+// NewIterator returns an Iterator that fetches pages with fetch, starting at
+// the page selected by o.
+func NewIterator[T any](o ListOptions, fetch PageFn[T]) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, opts: o}
+}
+
+// Next advances the Iterator to the next item, fetching the next page if
+// necessary. It returns false when there are no more items or an error
+// occurred.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		items, total, err := it.fetch(ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.total = items, total
+		it.opts.Offset += len(items)
+		// Stop at the end of the list, or if the server returns an empty
+		// page before reaching its advertised total.
+		it.done = len(items) == 0 || it.opts.Offset >= total
+		if len(it.page) == 0 {
+			return false
+		}
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Total returns the total number of items the server reported, once the
+// first page has been fetched.
+func (it *Iterator[T]) Total() int {
+	return it.total
+}
+
+// Err returns the error that stopped the Iterator, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All consumes the Iterator and returns the remaining items.
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var out []T
+	for it.Next(ctx) {
+		out = append(out, it.Value())
+	}
+	return out, it.Err()
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+	"crossplane-provider-codefresh/internal/client/fake"
+)
+
+func TestIterateProjects(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		opts   ProjectListOptions
+		want   []string
+	}{
+		"AllPages": {
+			reason: "All projects should be returned across pages.",
+			opts:   ProjectListOptions{ListOptions: ListOptions{Limit: 2}},
+			want:   []string{"api", "api-gateway", "web", "worker", "docs"},
+		},
+		"Offset": {
+			reason: "Iteration should start at the supplied offset.",
+			opts:   ProjectListOptions{ListOptions: ListOptions{Limit: 2, Offset: 3}},
+			want:   []string{"worker", "docs"},
+		},
+		"Name": {
+			reason: "Only projects whose name contains the filter should be returned.",
+			opts:   ProjectListOptions{ListOptions: ListOptions{Limit: 1}, Name: "api"},
+			want:   []string{"api", "api-gateway"},
+		},
+		"Tags": {
+			reason: "Only projects with all of the tags should be returned.",
+			opts:   ProjectListOptions{Tags: []string{"team-a", "prod"}},
+			want:   []string{"api", "worker"},
+		},
+	}
+
+	c, srv := newTestClient(t)
+	for _, p := range []v1alpha1.ProjectDetails{
+		{ProjectName: "api", ProjectTags: []string{"team-a", "prod"}},
+		{ProjectName: "api-gateway", ProjectTags: []string{"team-a"}},
+		{ProjectName: "web", ProjectTags: []string{"team-b", "prod"}},
+		{ProjectName: "worker", ProjectTags: []string{"prod", "team-a"}},
+		{ProjectName: "docs"},
+	} {
+		srv.AddProject(p)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := c.Projects().Iterate(tc.opts).All(context.Background())
+			if err != nil {
+				t.Fatalf("\n%s\nAll(...): %v", tc.reason, err)
+			}
+			names := make([]string, len(got))
+			for i, p := range got {
+				names[i] = p.ProjectName
+			}
+			if diff := cmp.Diff(tc.want, names); diff != "" {
+				t.Errorf("\n%s\nAll(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIteratePipelines(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		opts   func(projectID string) PipelineListOptions
+		want   []string
+	}{
+		"AllPages": {
+			reason: "All pipelines should be returned across pages.",
+			opts:   func(string) PipelineListOptions { return PipelineListOptions{ListOptions: ListOptions{Limit: 2}} },
+			want:   []string{"a/build", "a/deploy", "b/build"},
+		},
+		"Name": {
+			reason: "Only pipelines whose name contains the filter should be returned.",
+			opts:   func(string) PipelineListOptions { return PipelineListOptions{Name: "build"} },
+			want:   []string{"a/build", "b/build"},
+		},
+		"Project": {
+			reason: "Only pipelines of the named project should be returned.",
+			opts:   func(string) PipelineListOptions { return PipelineListOptions{Project: "a"} },
+			want:   []string{"a/build", "a/deploy"},
+		},
+		"ProjectID": {
+			reason: "Only pipelines of the project with the ID should be returned.",
+			opts:   func(id string) PipelineListOptions { return PipelineListOptions{ProjectID: id} },
+			want:   []string{"b/build"},
+		},
+		"Tags": {
+			reason: "Only pipelines with all of the tags should be returned.",
+			opts:   func(string) PipelineListOptions { return PipelineListOptions{Tags: []string{"ci"}} },
+			want:   []string{"a/build", "b/build"},
+		},
+		"Labels": {
+			reason: "Only pipelines with all of the label values should be returned.",
+			opts: func(string) PipelineListOptions {
+				return PipelineListOptions{Labels: map[string][]string{"tags": {"ci", "release"}}}
+			},
+			want: []string{"b/build"},
+		},
+	}
+
+	c, srv := newTestClient(t)
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "a"})
+	projectB := srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "b"})
+	for _, p := range []struct {
+		name string
+		tags []string
+	}{
+		{name: "a/build", tags: []string{"ci"}},
+		{name: "a/deploy", tags: []string{"cd"}},
+		{name: "b/build", tags: []string{"ci", "release"}},
+	} {
+		srv.AddPipeline(v1alpha1.PipelineDocument{
+			Metadata: v1alpha1.PipelineMetadataResponse{Name: p.name, Labels: map[string][]string{"tags": p.tags}},
+		})
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := c.Pipelines().Iterate(tc.opts(projectB)).All(context.Background())
+			if err != nil {
+				t.Fatalf("\n%s\nAll(...): %v", tc.reason, err)
+			}
+			names := make([]string, len(got))
+			for i, p := range got {
+				names[i] = p.Metadata.Name
+			}
+			if diff := cmp.Diff(tc.want, names); diff != "" {
+				t.Errorf("\n%s\nAll(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	c, srv := newTestClient(t)
+	for _, n := range []string{"a", "b", "c"} {
+		srv.AddProject(v1alpha1.ProjectDetails{ProjectName: n})
+	}
+
+	it := c.Projects().Iterate(ProjectListOptions{ListOptions: ListOptions{Limit: 2}})
+	ctx := context.Background()
+	var got []string
+	for i := 0; it.Next(ctx); i++ {
+		got = append(got, it.Value().ProjectName)
+		if i == 0 {
+			// Fail the request for the second page.
+			srv.InjectFault(fake.Fault{PathPrefix: "/projects", Status: http.StatusInternalServerError, Times: 1})
+		}
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, got); diff != "" {
+		t.Errorf("the first page should be returned before the error: -want, +got:\n%s", diff)
+	}
+	if err := it.Err(); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Err(): want server error, got %v", err)
+	}
+	if it.Next(ctx) {
+		t.Error("Next(...): a failed iterator should not continue")
+	}
+}
+
+func TestIteratorStopsOnShortList(t *testing.T) {
+	calls := 0
+	it := NewIterator(ListOptions{}, func(_ context.Context, o ListOptions) ([]int, int, error) {
+		calls++
+		if o.Offset > 0 {
+			// The server claims more items than it returns.
+			return nil, 10, nil
+		}
+		return []int{1, 2}, 10, nil
+	})
+	got, err := it.All(context.Background())
+	if err != nil {
+		t.Fatalf("All(...): %v", err)
+	}
+	if diff := cmp.Diff([]int{1, 2}, got); diff != "" {
+		t.Errorf("All(...): -want, +got:\n%s", diff)
+	}
+	if calls != 2 || it.Total() != 10 {
+		t.Errorf("want 2 calls and a total of 10, got %d calls and a total of %d", calls, it.Total())
+	}
+
+	it = NewIterator(ListOptions{}, func(context.Context, ListOptions) ([]int, int, error) {
+		return nil, 0, errors.New("boom")
+	})
+	if _, err := it.All(context.Background()); err == nil {
+		t.Error("All(...): want error")
+	}
+}
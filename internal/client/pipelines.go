@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// PipelineListOptions filter and page a list of pipelines.
+type PipelineListOptions struct {
+	ListOptions
+
+	// Name selects pipelines whose name contains it.
+	Name string
+	// Project selects pipelines of the project with this name.
+	Project string
+	// ProjectID selects pipelines of the project with this ID.
+	ProjectID string
+	// Labels selects pipelines that have all of the supplied values for each
+	// label.
+	Labels map[string][]string
+	// Tags selects pipelines that have all of them. It is shorthand for the
+	// tags label.
+	Tags []string
+}
+
+func (o PipelineListOptions) values() url.Values {
+	v := o.ListOptions.values()
+	if o.Name != "" {
+		v.Set("name", o.Name)
+	}
+	if o.Project != "" {
+		v.Set("project", o.Project)
+	}
+	if o.ProjectID != "" {
+		v.Set("projectId", o.ProjectID)
+	}
+	keys := make([]string, 0, len(o.Labels))
+	for k := range o.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, l := range o.Labels[k] {
+			v.Add("labels."+k, l)
+		}
+	}
+	for _, t := range o.Tags {
+		v.Add("labels.tags", t)
+	}
+	return v
+}
+
+// PipelinePatchMetadata are the metadata of a pipeline a PipelinePatch may
+// change.
+type PipelinePatchMetadata struct {
+	Name      string                      `json:"name,omitempty"`
+	Labels    map[string][]string         `json:"labels,omitempty"`
+	Deprecate *v1alpha1.PipelineDeprecate `json:"deprecate,omitempty"`
+	// Revision is the revision of the pipeline the patch was made against.
+	// When it is set, CodeFresh rejects the patch with a conflict if the
+	// pipeline has been changed since.
+	Revision *int `json:"revision,omitempty"`
+}
+
+// PipelinePatch changes the metadata and spec fields of a pipeline that are
+// set.
+type PipelinePatch struct {
+	Metadata PipelinePatchMetadata         `json:"metadata"`
+	Spec     *v1alpha1.PipelineSpecRequest `json:"spec,omitempty"`
+}
+
+// PipelinesAPI manages CodeFresh pipelines.
+type PipelinesAPI interface {
+	Get(ctx context.Context, id string) (*v1alpha1.PipelineDocument, error)
+	GetByName(ctx context.Context, name string) (*v1alpha1.PipelineDocument, error)
+	List(ctx context.Context, o PipelineListOptions) (*v1alpha1.PipelineDetails, error)
+	// Iterate pages through all pipelines selected by o.
+	Iterate(o PipelineListOptions) *Iterator[v1alpha1.PipelineDocument]
+	Create(ctx context.Context, params v1alpha1.PipelineCreateParams) (*v1alpha1.CreatePipelineResponse, error)
+	Patch(ctx context.Context, id string, patch PipelinePatch) (*v1alpha1.PipelineDocument, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type pipelinesClient struct {
+	api *CodeFreshAPIClient
+}
+
+// Get returns the pipeline with the supplied ID.
+func (p *pipelinesClient) Get(ctx context.Context, id string) (*v1alpha1.PipelineDocument, error) {
+	out := &v1alpha1.PipelineDocument{}
+	if err := p.api.do(ctx, http.MethodGet, "/pipelines/"+url.PathEscape(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetByName returns the pipeline with the supplied full name, i.e.
+// project/pipeline.
+func (p *pipelinesClient) GetByName(ctx context.Context, name string) (*v1alpha1.PipelineDocument, error) {
+	// Pipelines are addressed by ID or name on the same endpoint.
+	return p.Get(ctx, name)
+}
+
+// List returns a page of pipelines.
+func (p *pipelinesClient) List(ctx context.Context, o PipelineListOptions) (*v1alpha1.PipelineDetails, error) {
+	out := &v1alpha1.PipelineDetails{}
+	if err := p.api.do(ctx, http.MethodGet, withQuery("/pipelines", o.values()), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Iterate pages through all pipelines selected by o.
+func (p *pipelinesClient) Iterate(o PipelineListOptions) *Iterator[v1alpha1.PipelineDocument] {
+	return IteratePipelines(p.List, o)
+}
+
+// IteratePipelines returns an Iterator over the pipelines list returns.
+func IteratePipelines(list func(context.Context, PipelineListOptions) (*v1alpha1.PipelineDetails, error), o PipelineListOptions) *Iterator[v1alpha1.PipelineDocument] {
+	return NewIterator(o.ListOptions, func(ctx context.Context, lo ListOptions) ([]v1alpha1.PipelineDocument, int, error) {
+		o.ListOptions = lo
+		page, err := list(ctx, o)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Docs, page.Count, nil
+	})
+}
+
+// Create creates a pipeline.
+func (p *pipelinesClient) Create(ctx context.Context, params v1alpha1.PipelineCreateParams) (*v1alpha1.CreatePipelineResponse, error) {
+	out := &v1alpha1.CreatePipelineResponse{}
+	if err := p.api.do(ctx, http.MethodPost, "/pipelines", params, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Patch updates the pipeline with the supplied ID.
+func (p *pipelinesClient) Patch(ctx context.Context, id string, patch PipelinePatch) (*v1alpha1.PipelineDocument, error) {
+	out := &v1alpha1.PipelineDocument{}
+	if err := p.api.do(ctx, http.MethodPatch, "/pipelines/"+url.PathEscape(id), patch, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the pipeline with the supplied ID.
+func (p *pipelinesClient) Delete(ctx context.Context, id string) error {
+	return p.api.do(ctx, http.MethodDelete, "/pipelines/"+url.PathEscape(id), nil, nil)
+}
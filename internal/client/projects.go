@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// ProjectListOptions filter and page a list of projects.
+type ProjectListOptions struct {
+	ListOptions
+
+	// Name selects projects whose name contains it.
+	Name string
+	// Tags selects projects that have all of them.
+	Tags []string
+}
+
+func (o ProjectListOptions) values() url.Values {
+	v := o.ListOptions.values()
+	if o.Name != "" {
+		v.Set("name", o.Name)
+	}
+	for _, t := range o.Tags {
+		v.Add("tags", t)
+	}
+	return v
+}
+
+// ProjectPage is a page of projects.
+type ProjectPage struct {
+	Projects []v1alpha1.ProjectDetails `json:"projects"`
+	Total    int                       `json:"total"`
+	Limit    int                       `json:"limit"`
+	Offset   int                       `json:"offset"`
+}
+
+// ProjectPatch changes the fields of a project that are set. An empty, non-nil
+// slice clears the corresponding field.
+type ProjectPatch struct {
+	Name      *string                     `json:"projectName,omitempty"`
+	Image     *string                     `json:"image,omitempty"`
+	Tags      *[]string                   `json:"tags,omitempty"`
+	Variables *[]v1alpha1.ProjectVariable `json:"variables,omitempty"`
+}
+
+// ProjectsAPI manages CodeFresh projects.
+type ProjectsAPI interface {
+	Get(ctx context.Context, id string) (*v1alpha1.ProjectDetails, error)
+	GetByName(ctx context.Context, name string) (*v1alpha1.ProjectDetails, error)
+	List(ctx context.Context, o ProjectListOptions) (*ProjectPage, error)
+	// Iterate pages through all projects selected by o.
+	Iterate(o ProjectListOptions) *Iterator[v1alpha1.ProjectDetails]
+	Create(ctx context.Context, params v1alpha1.ProjectCreateParams) (*v1alpha1.CreateProjectResponse, error)
+	Patch(ctx context.Context, id string, patch ProjectPatch) (*v1alpha1.ProjectDetails, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type projectsClient struct {
+	api *CodeFreshAPIClient
+}
+
+// Get returns the project with the supplied ID.
+func (p *projectsClient) Get(ctx context.Context, id string) (*v1alpha1.ProjectDetails, error) {
+	out := &v1alpha1.ProjectDetails{}
+	if err := p.api.do(ctx, http.MethodGet, "/projects/"+url.PathEscape(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetByName returns the project with the supplied name.
+func (p *projectsClient) GetByName(ctx context.Context, name string) (*v1alpha1.ProjectDetails, error) {
+	out := &v1alpha1.ProjectDetails{}
+	if err := p.api.do(ctx, http.MethodGet, "/projects/name/"+url.PathEscape(name), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List returns a page of projects.
+func (p *projectsClient) List(ctx context.Context, o ProjectListOptions) (*ProjectPage, error) {
+	out := &ProjectPage{}
+	if err := p.api.do(ctx, http.MethodGet, withQuery("/projects", o.values()), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Iterate pages through all projects selected by o.
+func (p *projectsClient) Iterate(o ProjectListOptions) *Iterator[v1alpha1.ProjectDetails] {
+	return IterateProjects(p.List, o)
+}
+
+// IterateProjects returns an Iterator over the projects list returns.
+func IterateProjects(list func(context.Context, ProjectListOptions) (*ProjectPage, error), o ProjectListOptions) *Iterator[v1alpha1.ProjectDetails] {
+	return NewIterator(o.ListOptions, func(ctx context.Context, lo ListOptions) ([]v1alpha1.ProjectDetails, int, error) {
+		o.ListOptions = lo
+		page, err := list(ctx, o)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Projects, page.Total, nil
+	})
+}
+
+// Create creates a project.
+func (p *projectsClient) Create(ctx context.Context, params v1alpha1.ProjectCreateParams) (*v1alpha1.CreateProjectResponse, error) {
+	out := &v1alpha1.CreateProjectResponse{}
+	if err := p.api.do(ctx, http.MethodPost, "/projects", params, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Patch updates the project with the supplied ID.
+func (p *projectsClient) Patch(ctx context.Context, id string, patch ProjectPatch) (*v1alpha1.ProjectDetails, error) {
+	out := &v1alpha1.ProjectDetails{}
+	if err := p.api.do(ctx, http.MethodPatch, "/projects/"+url.PathEscape(id), patch, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the project with the supplied ID.
+func (p *projectsClient) Delete(ctx context.Context, id string) error {
+	return p.api.do(ctx, http.MethodDelete, "/projects/"+url.PathEscape(id), nil, nil)
+}
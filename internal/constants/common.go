@@ -6,6 +6,5 @@ const (
 	ErrGetCreds     = "cannot get credentials"
 	ErrNewClient    = "cannot create new Service"
 
-	ErrAssertCodeFreshService  = "cannot assert service as CodeFreshAPI"
 	ErrExpectedCodeFreshClient = "expected a CodeFreshAPIClient"
 )
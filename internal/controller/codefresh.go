@@ -22,6 +22,7 @@ import (
 
 	"crossplane-provider-codefresh/internal/controller/config"
 	"crossplane-provider-codefresh/internal/controller/pipeline"
+	"crossplane-provider-codefresh/internal/controller/pipelinerun"
 	"crossplane-provider-codefresh/internal/controller/project"
 )
 
@@ -32,6 +33,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		config.Setup,
 		project.Setup,
 		pipeline.Setup,
+		pipelinerun.Setup,
 	} {
 		if err := setup(mgr, o); err != nil {
 			return err
@@ -0,0 +1,447 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+
+	"crossplane-provider-codefresh/apis"
+	resourcev1alpha1 "crossplane-provider-codefresh/apis/resource/v1alpha1"
+	apisv1alpha1 "crossplane-provider-codefresh/apis/v1alpha1"
+	codefreshclient "crossplane-provider-codefresh/internal/client"
+	"crossplane-provider-codefresh/internal/client/fake"
+)
+
+// The integration tests run the controllers against a real API server. They
+// need the binaries installed by setup-envtest and are skipped unless
+// KUBEBUILDER_ASSETS points at them.
+
+const (
+	envtestAPIKey  = "api-key"
+	envtestTimeout = 30 * time.Second
+	envtestPoll    = 250 * time.Millisecond
+)
+
+var (
+	kube   client.Client
+	server *fake.Server
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		os.Exit(m.Run())
+	}
+	os.Exit(runEnvtest(m))
+}
+
+func runEnvtest(m *testing.M) int {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	env := &envtest.Environment{
+		Scheme: s,
+		CRDInstallOptions: envtest.CRDInstallOptions{
+			Paths:              []string{filepath.Join("..", "..", "package", "crds")},
+			ErrorIfPathMissing: true,
+		},
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "package", "webhookconfigurations")},
+		},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer env.Stop() //nolint:errcheck // Nothing to do if stopping fails.
+
+	server = fake.NewServer(fake.WithAPIKey(envtestAPIKey))
+	defer server.Close()
+
+	wo := env.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             s,
+		MetricsBindAddress: "0",
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    wo.LocalServingHost,
+			Port:    wo.LocalServingPort,
+			CertDir: wo.LocalServingCertDir,
+		}),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	o := controller.Options{
+		Logger:                  logging.NewNopLogger(),
+		MaxConcurrentReconciles: 1,
+		PollInterval:            time.Second,
+		GlobalRateLimiter:       ratelimiter.NewGlobal(100),
+		Features:                &feature.Flags{},
+	}
+	if err := Setup(mgr, o); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := (&resourcev1alpha1.Pipeline{}).SetupWebhookWithManager(mgr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := (&resourcev1alpha1.Project{}).SetupWebhookWithManager(mgr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}()
+
+	kube, err = client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := createProviderConfig(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+func createProviderConfig(ctx context.Context) error {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-system"}}
+	if err := kube.Create(ctx, ns); err != nil {
+		return err
+	}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns.Name, Name: "codefresh"},
+		StringData: map[string]string{"credentials": envtestAPIKey},
+	}
+	if err := kube.Create(ctx, sec); err != nil {
+		return err
+	}
+	pc := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: ns.Name, Name: sec.Name},
+						Key:             "credentials",
+					},
+				},
+			},
+			Endpoint: pointer.String(server.URL),
+		},
+	}
+	return kube.Create(ctx, pc)
+}
+
+func skipWithoutEnvtest(t *testing.T) {
+	t.Helper()
+	if kube == nil {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+}
+
+// eventually polls fn until it returns nil, failing the test with the last
+// error once the timeout expires.
+func eventually(t *testing.T, what string, fn func(ctx context.Context) error) {
+	t.Helper()
+	var last error
+	err := wait.PollUntilContextTimeout(context.Background(), envtestPoll, envtestTimeout, true, func(ctx context.Context) (bool, error) {
+		last = fn(ctx)
+		return last == nil, nil
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", what, last)
+	}
+}
+
+func ready(mg interface {
+	GetCondition(xpv1.ConditionType) xpv1.Condition
+}) error {
+	for _, c := range []xpv1.Condition{xpv1.Available(), xpv1.ReconcileSuccess()} {
+		got := mg.GetCondition(c.Type)
+		if got.Status != c.Status || got.Reason != c.Reason {
+			return fmt.Errorf("want condition %s=%s (%s), got %s (%s): %s", c.Type, c.Status, c.Reason, got.Status, got.Reason, got.Message)
+		}
+	}
+	return nil
+}
+
+func hasEvent(ctx context.Context, name, reason string) error {
+	l := &corev1.EventList{}
+	if err := kube.List(ctx, l); err != nil {
+		return err
+	}
+	for _, e := range l.Items {
+		if e.InvolvedObject.Name == name && e.Reason == reason {
+			return nil
+		}
+	}
+	return fmt.Errorf("no %s event for %s", reason, name)
+}
+
+func hasUsage(ctx context.Context, uid types.UID) error {
+	l := &apisv1alpha1.ProviderConfigUsageList{}
+	if err := kube.List(ctx, l); err != nil {
+		return err
+	}
+	for _, u := range l.Items {
+		if u.ResourceReference.UID == uid && u.ProviderConfigReference.Name == "default" {
+			return nil
+		}
+	}
+	return fmt.Errorf("no ProviderConfigUsage for resource %s", uid)
+}
+
+func TestProjectIntegration(t *testing.T) {
+	skipWithoutEnvtest(t)
+
+	cr := &resourcev1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-project"},
+		Spec: resourcev1alpha1.ProjectSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+			ForProvider: resourcev1alpha1.ProjectParameters{
+				ProjectName: "integration-project",
+				ProjectTags: []string{"a"},
+			},
+		},
+	}
+	if err := kube.Create(context.Background(), cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	key := client.ObjectKeyFromObject(cr)
+
+	eventually(t, "project should become ready", func(ctx context.Context) error {
+		if err := kube.Get(ctx, key, cr); err != nil {
+			return err
+		}
+		return ready(cr)
+	})
+	id := cr.Status.AtProvider.ProjectID
+	if got := meta.GetExternalName(cr); got != id {
+		t.Errorf("external name: want %q, got %q", id, got)
+	}
+	eventually(t, "project creation should be recorded", func(ctx context.Context) error {
+		return hasEvent(ctx, cr.GetName(), "CreatedExternalResource")
+	})
+	eventually(t, "project should be tracked as using the ProviderConfig", func(ctx context.Context) error {
+		return hasUsage(ctx, cr.GetUID())
+	})
+
+	// Update the desired tags and wait for them to reach CodeFresh.
+	eventually(t, "project tags should be updated", func(ctx context.Context) error {
+		if err := kube.Get(ctx, key, cr); err != nil {
+			return err
+		}
+		cr.Spec.ForProvider.ProjectTags = []string{"a", "b"}
+		return kube.Update(ctx, cr)
+	})
+	eventually(t, "updated tags should reach CodeFresh", func(ctx context.Context) error {
+		return projectTags(id, []string{"a", "b"})
+	})
+	eventually(t, "project update should be recorded", func(ctx context.Context) error {
+		return hasEvent(ctx, cr.GetName(), "UpdatedExternalResource")
+	})
+
+	// Change the project behind the provider's back and wait for the next
+	// poll to repair the drift.
+	cf := codefreshclient.NewCodeFreshAPIClient(envtestAPIKey, server.URL, logging.NewNopLogger())
+	drifted := []string{"drifted"}
+	if _, err := cf.Projects().Patch(context.Background(), id, codefreshclient.ProjectPatch{Tags: &drifted}); err != nil {
+		t.Fatalf("Patch(...): %v", err)
+	}
+	eventually(t, "drifted tags should be repaired", func(ctx context.Context) error {
+		return projectTags(id, []string{"a", "b"})
+	})
+
+	if err := kube.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	eventually(t, "project should be deleted", func(ctx context.Context) error {
+		if _, ok := server.Project(id); ok {
+			return fmt.Errorf("project %s still exists in CodeFresh", id)
+		}
+		if err := kube.Get(ctx, key, cr); !kerrors.IsNotFound(err) {
+			return fmt.Errorf("want managed resource to be gone, got %v", err)
+		}
+		return nil
+	})
+}
+
+func projectTags(id string, want []string) error {
+	p, ok := server.Project(id)
+	if !ok {
+		return fmt.Errorf("project %s does not exist in CodeFresh", id)
+	}
+	if diff := cmp.Diff(want, p.ProjectTags); diff != "" {
+		return fmt.Errorf("-want tags, +got tags:\n%s", diff)
+	}
+	return nil
+}
+
+func TestPipelineIntegration(t *testing.T) {
+	skipWithoutEnvtest(t)
+
+	server.AddProject(resourcev1alpha1.ProjectDetails{ProjectName: "pipelines"})
+	cr := &resourcev1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "integration-pipeline"},
+		Spec: resourcev1alpha1.PipelineSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+			ForProvider: resourcev1alpha1.PipelineParameters{
+				Metadata: resourcev1alpha1.PipelineMetadata{Name: "pipelines/integration"},
+			},
+		},
+	}
+	if err := kube.Create(context.Background(), cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	key := client.ObjectKeyFromObject(cr)
+
+	eventually(t, "pipeline should become ready", func(ctx context.Context) error {
+		if err := kube.Get(ctx, key, cr); err != nil {
+			return err
+		}
+		return ready(cr)
+	})
+	id := cr.Status.AtProvider.ID
+	if got := meta.GetExternalName(cr); got != id {
+		t.Errorf("external name: want %q, got %q", id, got)
+	}
+	// The stages CodeFresh defaults should be late-initialized.
+	if diff := cmp.Diff([]string{"clone", "build", "test"}, cr.Spec.ForProvider.Spec.Stages); diff != "" {
+		t.Errorf("late-initialized stages: -want, +got:\n%s", diff)
+	}
+	eventually(t, "pipeline creation should be recorded", func(ctx context.Context) error {
+		return hasEvent(ctx, cr.GetName(), "CreatedExternalResource")
+	})
+	eventually(t, "pipeline should be tracked as using the ProviderConfig", func(ctx context.Context) error {
+		return hasUsage(ctx, cr.GetUID())
+	})
+
+	// Update the desired priority and wait for it to reach CodeFresh.
+	eventually(t, "pipeline priority should be updated", func(ctx context.Context) error {
+		if err := kube.Get(ctx, key, cr); err != nil {
+			return err
+		}
+		cr.Spec.ForProvider.Spec.Priority = pointer.Int(3)
+		return kube.Update(ctx, cr)
+	})
+	eventually(t, "updated priority should reach CodeFresh", func(ctx context.Context) error {
+		return pipelinePriority(id, 3)
+	})
+	eventually(t, "pipeline update should be recorded", func(ctx context.Context) error {
+		return hasEvent(ctx, cr.GetName(), "UpdatedExternalResource")
+	})
+
+	// Change the pipeline behind the provider's back. The change is not
+	// overwritten until its revision is accepted, and the next poll then
+	// repairs the drift.
+	server.EditPipeline(id, func(p *resourcev1alpha1.PipelineDocument) { p.Spec.Priority = pointer.Int(7) })
+	eventually(t, "drift should be reported as a conflict", func(ctx context.Context) error {
+		if err := kube.Get(ctx, key, cr); err != nil {
+			return err
+		}
+		if c := cr.GetCondition(resourcev1alpha1.TypeConflictDetected); c.Status != corev1.ConditionTrue {
+			return fmt.Errorf("want condition %s=True, got %s: %s", c.Type, c.Status, c.Message)
+		}
+		return nil
+	})
+	if err := pipelinePriority(id, 7); err != nil {
+		t.Errorf("conflicting change should be kept: %v", err)
+	}
+	eventually(t, "conflicting revision should be accepted", func(ctx context.Context) error {
+		if err := kube.Get(ctx, key, cr); err != nil {
+			return err
+		}
+		meta.AddAnnotations(cr, map[string]string{resourcev1alpha1.AnnotationKeyAcceptRevision: strconv.Itoa(cr.Status.AtProvider.Revision)})
+		return kube.Update(ctx, cr)
+	})
+	eventually(t, "drifted priority should be repaired", func(ctx context.Context) error {
+		return pipelinePriority(id, 3)
+	})
+
+	if err := kube.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	eventually(t, "pipeline should be deleted", func(ctx context.Context) error {
+		if _, ok := server.Pipeline(id); ok {
+			return fmt.Errorf("pipeline %s still exists in CodeFresh", id)
+		}
+		if err := kube.Get(ctx, key, cr); !kerrors.IsNotFound(err) {
+			return fmt.Errorf("want managed resource to be gone, got %v", err)
+		}
+		return nil
+	})
+	eventually(t, "pipeline deletion should be recorded", func(ctx context.Context) error {
+		return hasEvent(ctx, cr.GetName(), "DeletedExternalResource")
+	})
+}
+
+func pipelinePriority(id string, want int) error {
+	p, ok := server.Pipeline(id)
+	if !ok {
+		return fmt.Errorf("pipeline %s does not exist in CodeFresh", id)
+	}
+	if p.Spec.Priority == nil || *p.Spec.Priority != want {
+		return fmt.Errorf("want priority %d, got %v", want, p.Spec.Priority)
+	}
+	return nil
+}
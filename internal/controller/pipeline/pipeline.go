@@ -19,25 +19,36 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"crossplane-provider-codefresh/apis/resource/v1alpha1"
 	apisv1alpha1 "crossplane-provider-codefresh/apis/v1alpha1"
+	"crossplane-provider-codefresh/internal/drift"
+	"crossplane-provider-codefresh/internal/dryrun"
 	"crossplane-provider-codefresh/internal/features"
 	"crossplane-provider-codefresh/internal/helpers"
+	"crossplane-provider-codefresh/internal/normalize"
 
 	codefreshclient "crossplane-provider-codefresh/internal/client"
 	"crossplane-provider-codefresh/internal/constants"
@@ -47,8 +58,22 @@ const (
 	errNotPipeline        = "managed resource is not a Pipeline custom resource"
 	errorFetchingPipeline = "Error occurred while fetching pipeline details"
 	errCreatingPipeline   = "error creating pipeline"
+	errLookingUpPipeline  = "error looking up existing pipeline by name"
+	errGettingPipeline    = "error getting pipeline"
+	errGettingAnnotations = "error getting pipeline annotations"
+	errSettingAnnotation  = "error setting pipeline annotation"
+	errDeletingAnnotation = "error deleting pipeline annotation"
+	errResolvingVariables = "error resolving pipeline variables"
 	errUpdatingPipeline   = "error updating pipeline"
+	errPipelineConflict   = "pipeline changed in CodeFresh since it was last applied"
 	errDeletingPipeline   = "something went wrong while deleting the pipeline"
+	errFmtForeignSecrets  = "refusing to update pipeline variables: CodeFresh replaces all of them but masks encrypted ones, so sending them would remove the encrypted variable(s) %s added elsewhere"
+
+	reasonDriftDetected event.Reason = "DriftDetected"
+
+	reasonVariablesUpdateRefused event.Reason = "VariablesUpdateRefused"
+
+	msgFmtConflict = "CodeFresh rejected an update made against revision %d: the pipeline changed since the provider last applied it. It is not updated until its spec changes, or revision %d is accepted with the %s annotation"
 
 	debugObservingPipelineResource = "Observing Pipeline resource"
 	debugPipelineIDNotFound        = "Pipeline ID not found in status; pipeline resource not created yet"
@@ -57,26 +82,33 @@ const (
 // Setup adds a controller that reconciles Pipeline managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.PipelineGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
 	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.PipelineGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(dryrun.NewConnecter(&connector{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: func(creds []byte, logger logging.Logger) (interface{}, error) {
-				return codefreshclient.NewCodeFreshService(creds, o.Logger)
+			newServiceFn: func(creds []byte, endpoint string, logger logging.Logger) (codefreshclient.CodeFreshAPI, error) {
+				return codefreshclient.NewCodeFreshService(creds, endpoint, o.Logger)
 			},
-			logger: o.Logger.WithValues("controller", name),
-		}),
+			logger:   o.Logger.WithValues("controller", name),
+			recorder: recorder,
+		}, v1alpha1.PipelineKind, o.Features.Enabled(features.DryRun), recorder)),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.PipelineGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -91,8 +123,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds []byte, logger logging.Logger) (interface{}, error)
+	newServiceFn func(creds []byte, endpoint string, logger logging.Logger) (codefreshclient.CodeFreshAPI, error)
 	logger       logging.Logger
+	recorder     event.Recorder
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -118,18 +151,12 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, constants.ErrGetCreds)
 	}
 
-	svc, err := c.newServiceFn(data, c.logger)
+	service, err := c.newServiceFn(data, pointer.StringDeref(pc.Spec.Endpoint, ""), c.logger)
 	if err != nil {
 		return nil, errors.Wrap(err, constants.ErrNewClient)
 	}
 
-	// Assert the type of svc to CodeFreshAPI
-	service, ok := svc.(codefreshclient.CodeFreshAPI)
-	if !ok {
-		return nil, errors.New(constants.ErrAssertCodeFreshService)
-	}
-
-	return newExternal(c.kube, c.logger, service), nil
+	return newExternal(c.kube, c.logger, c.recorder, service), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -137,15 +164,17 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	client client.Client
 	/*service interface{}*/
-	service codefreshclient.CodeFreshAPI
-	logger  logging.Logger
+	service  codefreshclient.CodeFreshAPI
+	logger   logging.Logger
+	recorder event.Recorder
 }
 
-func newExternal(client client.Client, logger logging.Logger, service codefreshclient.CodeFreshAPI) *external {
+func newExternal(client client.Client, logger logging.Logger, recorder event.Recorder, service codefreshclient.CodeFreshAPI) *external {
 	return &external{
-		client:  client,
-		logger:  logger,
-		service: service,
+		client:   client,
+		logger:   logger,
+		recorder: recorder,
+		service:  service,
 	}
 }
 
@@ -158,78 +187,814 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	c.logger.Debug(debugObservingPipelineResource, "name", cr.GetName())
 
-	pipelineID := cr.Status.AtProvider.ID
+	pipelineID := externalID(cr)
 	if pipelineID == "" {
 		c.logger.Debug(debugPipelineIDNotFound)
 		// No pipeline ID means the pipeline hasn't been created yet.
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	var pipelineDetails v1alpha1.PipelineDetails
-	err := c.service.GetResource(ctx, "pipelines", pipelineID, &pipelineDetails)
+	pipeline, err := c.service.Pipelines().Get(ctx, pipelineID)
 	if err != nil {
 		c.logger.Debug(errorFetchingPipeline, "error", err, "pipelineID", pipelineID)
 		// Check if the error is due to the pipeline not being found
-		if errors.Is(err, codefreshclient.ErrResourceNotFound) || helpers.IsResourceNotFoundErr(err, "Pipeline") {
+		if codefreshclient.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{}, err
 	}
 
-	nameUpToDate := false
-	if len(pipelineDetails.Docs) > 0 {
-		nameUpToDate = pipelineDetails.Docs[0].Metadata.Name == cr.Spec.ForProvider.Metadata.Name
-		c.logger.Debug("Comparing pipeline names", "observedName", pipelineDetails.Docs[0].Metadata.Name, "expectedName", cr.Spec.ForProvider.Metadata.Name)
-	} else {
-		c.logger.Debug("No documents found in pipeline details")
+	cr.Status.AtProvider.ID = pipelineID
+	// Updates are made against the observed revision.
+	cr.Status.AtProvider.Revision = pipeline.Metadata.Revision
+	cr.SetConditions(xpv1.Available())
+	observeDeprecation(cr, pipeline.Metadata.Deprecate)
+
+	current := cr.Spec.ForProvider.DeepCopy()
+
+	lateInitialize(&cr.Spec.ForProvider, pipeline)
+
+	var diffs []drift.Difference
+	if want, got := cr.Spec.ForProvider.Metadata.Name, pipeline.Metadata.Name; got != want {
+		diffs = append(diffs, drift.Field("metadata.name", want, got))
 	}
+	c.logger.Debug("Comparing pipeline names", "observedName", pipeline.Metadata.Name, "expectedName", cr.Spec.ForProvider.Metadata.Name)
 
-	resourceUpToDate := nameUpToDate
+	// Labels are only managed when they are set.
+	observed := managedEntries(cr, pipeline)
+	if cr.Spec.ForProvider.Metadata.Labels != nil {
+		if want, got := normalize.Labels(cr.Spec.ForProvider.Metadata.Labels), normalize.Labels(observed.Metadata.Labels); !cmp.Equal(got, want) {
+			diffs = append(diffs, drift.Field("metadata.labels", want, got))
+		}
+	}
+
+	if want, owned := desiredAnnotations(cr), cr.Status.AtProvider.AnnotationKeys; len(want) > 0 || len(owned) > 0 {
+		got, err := c.service.Annotations().List(ctx, v1alpha1.AnnotationEntityPipeline, pipelineID)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGettingAnnotations)
+		}
+		diffs = append(diffs, annotationsDrift(want, owned, got)...)
+		// Recorded here, since status set by Create may be discarded.
+		cr.Status.AtProvider.AnnotationKeys = ownedAnnotations(want, owned, got)
+	}
+
+	// Deprecation is only managed when it is set.
+	if want := cr.Spec.ForProvider.Metadata.Deprecate; want != nil && !isDeprecateUpToDate(want, pipeline.Metadata.Deprecate) {
+		diffs = append(diffs, drift.Field("metadata.deprecate", want, pipeline.Metadata.Deprecate))
+	}
+
+	diffs = append(diffs, stepSourceDrift(&cr.Spec.ForProvider.Spec, cr.GetStepOrder(), &pipeline.Spec)...)
+
+	diffs = append(diffs, optionsDrift(&cr.Spec.ForProvider.Spec, &pipeline.Spec)...)
+
+	diffs = append(diffs, concurrencyDrift(&cr.Spec.ForProvider.Spec, &pipeline.Spec)...)
+
+	// The termination policy is only managed when it is set.
+	if want := cr.Spec.ForProvider.Spec.TerminationPolicy; want != nil {
+		diffs = append(diffs, terminationPolicyDrift(want, pipeline.Spec.TerminationPolicy)...)
+	}
+
+	// Variables are only managed when they are set.
+	if declared := cr.Spec.ForProvider.Spec.Variables; declared != nil {
+		want, err := c.resolveVariables(ctx, declared)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errResolvingVariables)
+		}
+		diffs = append(diffs, variablesDrift(declared, want, observed.Spec.Variables, cr.Status.AtProvider.VariableHashes, cr.GetUID())...)
+	}
+
+	// Triggers are only managed when they are set. In Additive mode only
+	// those that are declared or owned are compared.
+	if declared := cr.Spec.ForProvider.Spec.Triggers; declared != nil {
+		want := make([]v1alpha1.PipelineTrigger, len(declared))
+		for i, t := range declared {
+			want[i] = t
+			if want[i].Variables, err = c.resolveVariables(ctx, t.Variables); err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, errResolvingVariables)
+			}
+		}
+		diffs = append(diffs, triggersDrift(declared, want, observed.Spec.Triggers, cr.Status.AtProvider.TriggerVariableHashes, cr.GetUID())...)
+	}
+
+	// External resources are only managed when they are set.
+	if want := cr.Spec.ForProvider.Spec.ExternalResources; want != nil {
+		diffs = append(diffs, externalResourcesDrift(want, pipeline.Spec.ExternalResources)...)
+	}
+
+	resourceUpToDate := len(diffs) == 0
+	c.observeDrift(cr, diffs)
+	if resourceUpToDate {
+		// Status set by Create may have been discarded, so ownership is
+		// recorded again once CodeFresh holds the declared entries.
+		cr.Status.AtProvider.Owned = declaredEntries(cr)
+	}
 
 	c.logger.Debug("Observed pipeline resource", "resourceUpToDate", resourceUpToDate)
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  resourceUpToDate,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:          true,
+		ResourceUpToDate:        resourceUpToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		ConnectionDetails:       managed.ConnectionDetails{},
 	}, nil
 }
 
+// observeDrift records a summary of the supplied differences in the status
+// and Drifted condition of the pipeline, and emits an event when the summary
+// changes. A pipeline that never drifted gets no condition at all.
+func (c *external) observeDrift(cr *v1alpha1.Pipeline, diffs []drift.Difference) {
+	if len(diffs) == 0 {
+		cr.Status.AtProvider.Drift = ""
+		if cr.GetCondition(v1alpha1.TypeDrifted).Status == corev1.ConditionTrue {
+			cr.SetConditions(v1alpha1.NotDrifted())
+		}
+		return
+	}
+	summary := drift.Summary(diffs, drift.MaxSummaryLength)
+	if summary != cr.Status.AtProvider.Drift {
+		c.recorder.Event(cr, event.Normal(reasonDriftDetected, summary))
+	}
+	cr.Status.AtProvider.Drift = summary
+	cr.SetConditions(v1alpha1.Drifted().WithMessage(summary))
+}
+
+// externalID returns the CodeFresh ID of the pipeline. The ID recorded in
+// status takes precedence; otherwise an external name that was set explicitly,
+// for example to observe an existing pipeline, is used.
+func externalID(cr *v1alpha1.Pipeline) string {
+	if cr.Status.AtProvider.ID != "" {
+		return cr.Status.AtProvider.ID
+	}
+	if en := meta.GetExternalName(cr); en != cr.GetName() {
+		return en
+	}
+	return ""
+}
+
+// isAdditive returns true if the pipeline is managed in Additive mode.
+func isAdditive(cr *v1alpha1.Pipeline) bool {
+	return cr.Spec.ForProvider.ManagementMode == v1alpha1.ManagementModeAdditive
+}
+
+// ownedEntries returns the entries the provider owned when it last synced the
+// pipeline.
+func ownedEntries(cr *v1alpha1.Pipeline) v1alpha1.OwnedEntries {
+	if cr.Status.AtProvider.Owned == nil {
+		return v1alpha1.OwnedEntries{}
+	}
+	return *cr.Status.AtProvider.Owned
+}
+
+// declaredEntries returns the entries the provider owns once CodeFresh holds
+// the declared ones: none in Authoritative mode, where it owns all entries.
+func declaredEntries(cr *v1alpha1.Pipeline) *v1alpha1.OwnedEntries {
+	if !isAdditive(cr) {
+		return nil
+	}
+	return &v1alpha1.OwnedEntries{
+		Tags:      append([]string(nil), cr.Spec.ForProvider.Metadata.Labels[v1alpha1.LabelKeyTags]...),
+		Variables: variableKeys(cr.Spec.ForProvider.Spec.Variables),
+		Triggers:  triggerNames(cr.Spec.ForProvider.Spec.Triggers),
+	}
+}
+
+// managedEntries returns the observed pipeline with the tags, variables and
+// triggers the provider does not manage removed. In Authoritative mode it
+// manages all of them, and the pipeline is returned as observed.
+func managedEntries(cr *v1alpha1.Pipeline, observed *v1alpha1.PipelineDocument) *v1alpha1.PipelineDocument {
+	if !isAdditive(cr) {
+		return observed
+	}
+	in := &cr.Spec.ForProvider
+	owned := ownedEntries(cr)
+	out := *observed
+	out.Metadata.Labels = withTags(observed.Metadata.Labels, helpers.OwnedOf(observed.Metadata.Labels[v1alpha1.LabelKeyTags], in.Metadata.Labels[v1alpha1.LabelKeyTags], owned.Tags, helpers.Identity))
+	out.Spec.Variables = helpers.OwnedOf(observed.Spec.Variables, variableKeys(in.Spec.Variables), owned.Variables, variableKey)
+	out.Spec.Triggers = helpers.OwnedOf(observed.Spec.Triggers, triggerNames(in.Spec.Triggers), owned.Triggers, triggerName)
+	return &out
+}
+
+// mergeEntries adds the tags, variables and triggers of the observed pipeline
+// that the provider does not manage to the supplied labels and spec, so that
+// a patch replacing them keeps those entries. Entries that are left unmanaged
+// are left unchanged. The merged labels are returned.
+func mergeEntries(cr *v1alpha1.Pipeline, spec *v1alpha1.PipelineSpecStruct, labels map[string][]string, observed *v1alpha1.PipelineDocument) map[string][]string {
+	owned := ownedEntries(cr)
+	if spec.Variables != nil {
+		spec.Variables = helpers.MergeOwned(spec.Variables, observed.Spec.Variables, owned.Variables, variableKey)
+	}
+	if spec.Triggers != nil {
+		spec.Triggers = helpers.MergeOwned(spec.Triggers, observed.Spec.Triggers, owned.Triggers, triggerName)
+	}
+	if labels == nil {
+		return nil
+	}
+	return withTags(labels, helpers.MergeOwned(labels[v1alpha1.LabelKeyTags], observed.Metadata.Labels[v1alpha1.LabelKeyTags], owned.Tags, helpers.Identity))
+}
+
+// withTags returns a copy of labels with their tags replaced.
+func withTags(labels map[string][]string, tags []string) map[string][]string {
+	out := make(map[string][]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+	if len(tags) == 0 {
+		delete(out, v1alpha1.LabelKeyTags)
+		return out
+	}
+	out[v1alpha1.LabelKeyTags] = tags
+	return out
+}
+
+// updateRevision returns the revision of the pipeline an update is made
+// against: the one the provider last applied, so that a change made elsewhere
+// since is not overwritten even though Observe has already seen it. Status
+// set in Create may be discarded, in which case it is the observed revision.
+// After a conflict the observed revision is used once the spec changed, or
+// once it was accepted with the accept-revision annotation.
+func updateRevision(cr *v1alpha1.Pipeline) int {
+	at := cr.Status.AtProvider
+	if g := at.ConflictGeneration; g != 0 && g != cr.GetGeneration() {
+		return at.Revision
+	}
+	if accepted, err := strconv.Atoi(cr.GetAnnotations()[v1alpha1.AnnotationKeyAcceptRevision]); err == nil && accepted == at.Revision {
+		return at.Revision
+	}
+	if at.AppliedRevision == 0 {
+		return at.Revision
+	}
+	return at.AppliedRevision
+}
+
+// variableKey returns the key of v.
+func variableKey(v v1alpha1.PipelineVariable) string {
+	return v.Key
+}
+
+// foreignSecrets returns the keys of the encrypted variables of the observed
+// pipeline that the provider does not manage.
+func foreignSecrets(cr *v1alpha1.Pipeline, observed *v1alpha1.PipelineDocument) []string {
+	var keys []string
+	for _, v := range helpers.UnownedOf(observed.Spec.Variables, variableKeys(cr.Spec.ForProvider.Spec.Variables), ownedEntries(cr).Variables, variableKey) {
+		if v.Encrypted {
+			keys = append(keys, v.Key)
+		}
+	}
+	return keys
+}
+
+// variableKeys returns the keys of the supplied variables.
+func variableKeys(vars []v1alpha1.PipelineVariable) []string {
+	if vars == nil {
+		return nil
+	}
+	keys := make([]string, len(vars))
+	for i, v := range vars {
+		keys[i] = v.Key
+	}
+	return keys
+}
+
+// triggerName returns the name of t.
+func triggerName(t v1alpha1.PipelineTrigger) string {
+	return t.Name
+}
+
+// triggerNames returns the names of the supplied triggers.
+func triggerNames(triggers []v1alpha1.PipelineTrigger) []string {
+	if triggers == nil {
+		return nil
+	}
+	names := make([]string, len(triggers))
+	for i, t := range triggers {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// resolveSpec returns a copy of the spec of the pipeline with the values of
+// its variables, and those of its triggers, read from their sources.
+func (c *external) resolveSpec(ctx context.Context, cr *v1alpha1.Pipeline) (*v1alpha1.PipelineSpecStruct, error) {
+	spec := cr.Spec.ForProvider.Spec.DeepCopy()
+	var err error
+	if spec.Variables, err = c.resolveVariables(ctx, spec.Variables); err != nil {
+		return nil, err
+	}
+	for i := range spec.Triggers {
+		if spec.Triggers[i].Variables, err = c.resolveVariables(ctx, spec.Triggers[i].Variables); err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+// resolveVariables returns a copy of the supplied variables with the values
+// of those read from a Secret or ConfigMap filled in.
+func (c *external) resolveVariables(ctx context.Context, vars []v1alpha1.PipelineVariable) ([]v1alpha1.PipelineVariable, error) {
+	if vars == nil {
+		return nil, nil
+	}
+	out := make([]v1alpha1.PipelineVariable, len(vars))
+	for i, v := range vars {
+		if v.ValueFrom != nil {
+			value, err := helpers.ResolveVariableValue(ctx, c.client, v.ValueFrom)
+			if err != nil {
+				return nil, errors.Wrapf(err, "variable %s", v.Key)
+			}
+			v.Value, v.ValueFrom = value, nil
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// variablesDrift returns the differences between the supplied variables and
+// those CodeFresh has, in any order. CodeFresh masks the values of encrypted
+// variables, so those are compared with the hashes of the values last sent
+// instead. The values of the declared variables read from a Secret are
+// redacted, as are those of encrypted variables.
+func variablesDrift(declared, want, got []v1alpha1.PipelineVariable, hashes map[string]string, salt types.UID) []drift.Difference {
+	secret := map[string]bool{}
+	for _, v := range declared {
+		if v.ValueFrom != nil && v.ValueFrom.SecretKeyRef != nil {
+			secret[v.Key] = true
+		}
+	}
+	same := func(w, o drift.Variable) bool {
+		switch {
+		case o.Encrypted != w.Encrypted:
+			return false
+		case w.Encrypted:
+			return hashes[w.Key] == helpers.VariableHash(salt, w.Key, w.Value)
+		}
+		return o.Value == w.Value
+	}
+	return drift.Variables("spec.variables", driftVariables(want, secret), driftVariables(got, secret), same)
+}
+
+// driftVariables returns the supplied variables, normalized, as compared for
+// drift.
+func driftVariables(vars []v1alpha1.PipelineVariable, secret map[string]bool) []drift.Variable {
+	vars = normalize.PipelineVariables(vars)
+	out := make([]drift.Variable, len(vars))
+	for i, v := range vars {
+		out[i] = drift.Variable{Key: v.Key, Value: v.Value, Encrypted: v.Encrypted, Secret: secret[v.Key]}
+	}
+	return out
+}
+
+// variableHashes returns the hashes of the values of the supplied encrypted
+// variables, by key.
+func variableHashes(salt types.UID, vars []v1alpha1.PipelineVariable) map[string]string {
+	var hashes map[string]string
+	for _, v := range vars {
+		if !v.Encrypted {
+			continue
+		}
+		if hashes == nil {
+			hashes = map[string]string{}
+		}
+		hashes[v.Key] = helpers.VariableHash(salt, v.Key, v.Value)
+	}
+	return hashes
+}
+
+// triggerVariableHashes returns the hashes of the values of the encrypted
+// variables of the supplied triggers, by trigger name and key.
+func triggerVariableHashes(salt types.UID, triggers []v1alpha1.PipelineTrigger) map[string]string {
+	var hashes map[string]string
+	for _, t := range triggers {
+		for _, v := range t.Variables {
+			if !v.Encrypted {
+				continue
+			}
+			if hashes == nil {
+				hashes = map[string]string{}
+			}
+			key := triggerVariableKey(t.Name, v.Key)
+			hashes[key] = helpers.VariableHash(salt, key, v.Value)
+		}
+	}
+	return hashes
+}
+
+// triggerVariableKey returns the key of the hash of a trigger variable.
+func triggerVariableKey(trigger, key string) string {
+	return trigger + "/" + key
+}
+
+// specRequest returns the supplied spec as sent to CodeFresh, with its steps
+// in the supplied order.
+func specRequest(in *v1alpha1.PipelineSpecStruct, order []string) v1alpha1.PipelineSpecRequest {
+	return v1alpha1.PipelineSpecRequest{
+		PipelineSpecStruct: v1alpha1.PipelineSpecStruct{
+			Triggers:     in.Triggers,
+			Stages:       in.Stages,
+			SpecTemplate: in.SpecTemplate,
+			Variables:    in.Variables,
+			Options:      in.Options,
+			// Contexts:  in.Contexts,
+
+			Concurrency:        in.Concurrency,
+			TriggerConcurrency: in.TriggerConcurrency,
+			BranchConcurrency:  in.BranchConcurrency,
+			Priority:           in.Priority,
+			TerminationPolicy:  in.TerminationPolicy,
+			ExternalResources:  in.ExternalResources,
+		},
+		OrderedSteps: v1alpha1.SortSteps(in.Steps, order),
+	}
+}
+
+// concurrencyDrift returns the differences between each concurrency limit
+// and the priority of the spec and their values in CodeFresh. Those that are
+// unset are not managed.
+func concurrencyDrift(want *v1alpha1.PipelineSpecStruct, got *v1alpha1.PipelineSpecResponse) []drift.Difference {
+	fields := []struct {
+		path      string
+		want, got *int
+	}{
+		{"spec.concurrency", want.Concurrency, got.Concurrency},
+		{"spec.triggerConcurrency", want.TriggerConcurrency, got.TriggerConcurrency},
+		{"spec.branchConcurrency", want.BranchConcurrency, got.BranchConcurrency},
+		{"spec.priority", want.Priority, got.Priority},
+	}
+	var out []drift.Difference
+	for _, f := range fields {
+		if !isIntUpToDate(f.want, f.got) {
+			out = append(out, drift.Field(f.path, f.want, f.got))
+		}
+	}
+	return out
+}
+
+// isIntUpToDate returns true if want is unset, or got is set to the same
+// value.
+func isIntUpToDate(want, got *int) bool {
+	return want == nil || (got != nil && *got == *want)
+}
+
+// terminationPolicyDrift returns the difference between the rules of the
+// policy and those CodeFresh has, unless they are the same in any order.
+func terminationPolicyDrift(want *v1alpha1.PipelineTerminationPolicy, got []v1alpha1.PipelineTerminationRule) []drift.Difference {
+	w, g := normalize.TerminationRules(want.Rules()), normalize.TerminationRules(got)
+	if cmp.Equal(w, g) {
+		return nil
+	}
+	return []drift.Difference{drift.Field("spec.terminationPolicy", w, g)}
+}
+
+// externalResourcesDrift returns the difference between the external
+// resources of the spec and those CodeFresh copies, unless they are the same
+// in the same order. CodeFresh copies them in order, so a later resource may
+// overwrite an earlier one and a reordering is drift too.
+func externalResourcesDrift(want, got []v1alpha1.PipelineExternalResource) []drift.Difference {
+	w, g := normalize.ExternalResources(want), normalize.ExternalResources(got)
+	if cmp.Equal(w, g) {
+		return nil
+	}
+	return []drift.Difference{drift.Field("spec.externalResources", w, g)}
+}
+
+// stepSourceDrift returns the difference between the source of the steps of
+// the spec and the source CodeFresh loads them from. Steps loaded from a spec
+// template are compared by the location of the template, not by the steps it
+// holds. Inline steps are only managed when they are set and are compared,
+// normalized by normalize.Steps, in the supplied order; CodeFresh runs them in
+// order, so a reordering is drift too.
+func stepSourceDrift(want *v1alpha1.PipelineSpecStruct, order []string, got *v1alpha1.PipelineSpecResponse) []drift.Difference {
+	switch {
+	case want.SpecTemplate != nil:
+		if w, g := normalize.SpecTemplate(want.SpecTemplate), normalize.SpecTemplate(got.SpecTemplate); !cmp.Equal(w, g) {
+			return []drift.Difference{drift.Field("spec.specTemplate", w, g)}
+		}
+	case want.Steps != nil:
+		if got.SpecTemplate != nil {
+			return []drift.Difference{drift.Field("spec.specTemplate", nil, normalize.SpecTemplate(got.SpecTemplate))}
+		}
+		if w, g := normalize.Steps(v1alpha1.SortSteps(want.Steps, order)), normalize.Steps(responseSteps(got.Steps)); !cmp.Equal(w, g) {
+			return []drift.Difference{drift.Field("spec.steps", w, g)}
+		}
+	}
+	return nil
+}
+
+// responseSteps returns the names and values of the supplied observed steps,
+// in order.
+func responseSteps(steps v1alpha1.PipelineStepsResponse) []v1alpha1.PipelineStep {
+	if steps == nil {
+		return nil
+	}
+	out := make([]v1alpha1.PipelineStep, len(steps))
+	for i, st := range steps {
+		out[i] = v1alpha1.PipelineStep{Name: st.Name, Values: st.Values}
+	}
+	return out
+}
+
+// optionsDrift returns the differences between the options and stages of
+// the spec and those CodeFresh has. Both are only managed when they are set,
+// and are otherwise late-initialized. CodeFresh runs stages in order, so a
+// reordering is drift too. A pipeline without options has none of them
+// enabled.
+func optionsDrift(want *v1alpha1.PipelineSpecStruct, got *v1alpha1.PipelineSpecResponse) []drift.Difference {
+	var out []drift.Difference
+	if want.Options != nil {
+		g := v1alpha1.PipelineOptions{}
+		if got.Options != nil {
+			g = *got.Options
+		}
+		if *want.Options != g {
+			out = append(out, drift.Field("spec.options", want.Options, g))
+		}
+	}
+	if len(want.Stages) > 0 && !cmp.Equal(want.Stages, got.Stages) {
+		out = append(out, drift.Field("spec.stages", want.Stages, got.Stages))
+	}
+	return out
+}
+
+// triggersDrift returns the differences between the supplied triggers and
+// those CodeFresh has, by trigger name. The settings of each trigger are
+// compared as normalized by normalize.Triggers, and its variables like those
+// of the pipeline: the values of encrypted variables are compared with the
+// hashes of the values last sent, by trigger name and key. The values of
+// declared variables read from a Secret are redacted, as are those of
+// encrypted variables.
+func triggersDrift(declared, want, got []v1alpha1.PipelineTrigger, hashes map[string]string, salt types.UID) []drift.Difference {
+	secret := map[string]map[string]bool{}
+	for _, t := range declared {
+		for _, v := range t.Variables {
+			if v.ValueFrom != nil && v.ValueFrom.SecretKeyRef != nil {
+				if secret[t.Name] == nil {
+					secret[t.Name] = map[string]bool{}
+				}
+				secret[t.Name][v.Key] = true
+			}
+		}
+	}
+
+	observed := map[string]v1alpha1.PipelineTrigger{}
+	for _, t := range normalize.Triggers(got) {
+		observed[t.Name] = t
+	}
+	var out []drift.Difference
+	for _, w := range normalize.Triggers(want) {
+		path := fmt.Sprintf("spec.triggers[%s]", w.Name)
+		g, ok := observed[w.Name]
+		if !ok {
+			out = append(out, drift.Field(path, triggerSettings(w), nil))
+			continue
+		}
+		delete(observed, w.Name)
+		if ws, gs := triggerSettings(w), triggerSettings(g); !cmp.Equal(ws, gs) {
+			out = append(out, drift.Field(path, ws, gs))
+		}
+		name := w.Name
+		same := func(w, o drift.Variable) bool {
+			switch {
+			case o.Encrypted != w.Encrypted:
+				return false
+			case w.Encrypted:
+				return hashes[triggerVariableKey(name, w.Key)] == helpers.VariableHash(salt, triggerVariableKey(name, w.Key), w.Value)
+			}
+			return o.Value == w.Value
+		}
+		out = append(out, drift.Variables(path+".variables", driftVariables(w.Variables, secret[w.Name]), driftVariables(g.Variables, secret[w.Name]), same)...)
+	}
+	for _, g := range normalize.Triggers(got) {
+		if _, ok := observed[g.Name]; ok {
+			out = append(out, drift.Field(fmt.Sprintf("spec.triggers[%s]", g.Name), nil, triggerSettings(g)))
+		}
+	}
+	return out
+}
+
+// triggerSettings returns the supplied trigger without its variables, which
+// are compared on their own.
+func triggerSettings(t v1alpha1.PipelineTrigger) v1alpha1.PipelineTrigger {
+	t.Variables = nil
+	return t
+}
+
+// observeDeprecation sets the Deprecated condition of the pipeline when
+// CodeFresh reports it deprecated. A pipeline that was never deprecated gets
+// no condition at all.
+func observeDeprecation(cr *v1alpha1.Pipeline, d *v1alpha1.PipelineDeprecate) {
+	if !d.IsSet() {
+		if cr.GetCondition(v1alpha1.TypeDeprecated).Status == corev1.ConditionTrue {
+			cr.SetConditions(v1alpha1.NotDeprecated())
+		}
+		return
+	}
+	var settings []string
+	if d.ApplicationPort != nil {
+		settings = append(settings, "applicationPort="+*d.ApplicationPort)
+	}
+	if d.RepoPipeline != nil {
+		settings = append(settings, "repoPipeline="+strconv.FormatBool(*d.RepoPipeline))
+	}
+	cr.SetConditions(v1alpha1.Deprecated().WithMessage("CodeFresh reports the pipeline deprecated: " + strings.Join(settings, ", ")))
+}
+
+// isDeprecateUpToDate returns true if each deprecated setting of the spec is
+// set to the same value in CodeFresh.
+func isDeprecateUpToDate(want, got *v1alpha1.PipelineDeprecate) bool {
+	if got == nil {
+		got = &v1alpha1.PipelineDeprecate{}
+	}
+	if want.ApplicationPort != nil && (got.ApplicationPort == nil || *got.ApplicationPort != *want.ApplicationPort) {
+		return false
+	}
+	if want.RepoPipeline != nil && (got.RepoPipeline == nil || *got.RepoPipeline != *want.RepoPipeline) {
+		return false
+	}
+	return true
+}
+
+// desiredAnnotations returns the CodeFresh annotations of the pipeline: the
+// annotations of its metadata, and the mirrored labels of the Pipeline.
+func desiredAnnotations(cr *v1alpha1.Pipeline) map[string]string {
+	out := map[string]string{}
+	for _, k := range cr.Spec.ForProvider.MirrorLabels {
+		if v, ok := cr.GetLabels()[k]; ok {
+			out[k] = v
+		}
+	}
+	for k, v := range cr.Spec.ForProvider.Metadata.Annotations {
+		out[k] = v
+	}
+	return out
+}
+
+// outdatedAnnotations returns the keys of the wanted annotations that are
+// missing from, or have a different value in, the supplied annotations, in
+// key order.
+func outdatedAnnotations(want map[string]string, got []v1alpha1.Annotation) []string {
+	have := make(map[string]string, len(got))
+	for _, a := range got {
+		have[a.Key] = a.Value
+	}
+	var out []string
+	for k, v := range want {
+		if hv, ok := have[k]; !ok || hv != v {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// staleAnnotations returns the keys of the owned annotations that are no
+// longer wanted but are still present in the supplied annotations, in key
+// order.
+func staleAnnotations(want map[string]string, owned []string, got []v1alpha1.Annotation) []string {
+	var out []string
+	for _, k := range owned {
+		if _, ok := want[k]; ok {
+			continue
+		}
+		for _, a := range got {
+			if a.Key == k {
+				out = append(out, k)
+				break
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ownedAnnotations returns the keys of the annotations the provider manages:
+// those it wants, and those it owned that are yet to be deleted, in key order.
+func ownedAnnotations(want map[string]string, owned []string, got []v1alpha1.Annotation) []string {
+	out := staleAnnotations(want, owned, got)
+	for k := range want {
+		out = append(out, k)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	sort.Strings(out)
+	return out
+}
+
+// annotationsDrift returns the differences between the wanted annotations
+// and the supplied annotations, including owned annotations that are no
+// longer wanted, in key order.
+func annotationsDrift(want map[string]string, owned []string, got []v1alpha1.Annotation) []drift.Difference {
+	keys := append(outdatedAnnotations(want, got), staleAnnotations(want, owned, got)...)
+	sort.Strings(keys)
+	out := make([]drift.Difference, 0, len(keys))
+	for _, k := range keys {
+		var desired, observed *string
+		if v, ok := want[k]; ok {
+			desired = pointer.String(v)
+		}
+		for _, a := range got {
+			if a.Key == k {
+				observed = pointer.String(a.Value)
+			}
+		}
+		out = append(out, drift.Field("metadata.annotations["+k+"]", desired, observed))
+	}
+	return out
+}
+
+// syncAnnotations sets the wanted annotations of the pipeline with the
+// supplied ID that are missing or differ in CodeFresh, and deletes the owned
+// annotations that are no longer wanted.
+func (c *external) syncAnnotations(ctx context.Context, cr *v1alpha1.Pipeline, id string) error {
+	want, owned := desiredAnnotations(cr), cr.Status.AtProvider.AnnotationKeys
+	if len(want) == 0 && len(owned) == 0 {
+		return nil
+	}
+	got, err := c.service.Annotations().List(ctx, v1alpha1.AnnotationEntityPipeline, id)
+	if err != nil {
+		return errors.Wrap(err, errGettingAnnotations)
+	}
+	for _, k := range outdatedAnnotations(want, got) {
+		a := v1alpha1.Annotation{EntityType: v1alpha1.AnnotationEntityPipeline, EntityID: id, Key: k, Value: want[k]}
+		if err := c.service.Annotations().Set(ctx, a); err != nil {
+			return errors.Wrap(err, errSettingAnnotation)
+		}
+	}
+	for _, k := range staleAnnotations(want, owned, got) {
+		if err := c.service.Annotations().Delete(ctx, v1alpha1.AnnotationEntityPipeline, id, k); err != nil {
+			return errors.Wrap(err, errDeletingAnnotation)
+		}
+	}
+	cr.Status.AtProvider.AnnotationKeys = ownedAnnotations(want, nil, nil)
+	return nil
+}
+
+// lateInitialize fills the unset optional fields of the supplied parameters
+// with the values CodeFresh defaulted them to.
+func lateInitialize(in *v1alpha1.PipelineParameters, doc *v1alpha1.PipelineDocument) {
+	if in.Spec.Options == nil && doc.Spec.Options != nil {
+		in.Spec.Options = doc.Spec.Options.DeepCopy()
+	}
+	in.Spec.Stages = helpers.LateInitializeStringSlice(in.Spec.Stages, doc.Spec.Stages)
+}
+
+// Create creates the pipeline, or adopts an existing pipeline with the same
+// project/name so that retrying a create whose ID was never recorded doesn't
+// duplicate it. The ID is returned through the external name, which the
+// managed reconciler persists together with its external-create annotations.
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.Pipeline)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotPipeline)
 	}
 
+	existing, err := c.service.Pipelines().GetByName(ctx, cr.Spec.ForProvider.Metadata.Name)
+	switch {
+	case err == nil:
+		c.logger.Debug("Adopting existing pipeline", "name", existing.Metadata.Name, "pipelineID", existing.Metadata.ID)
+		cr.Status.AtProvider.ID = existing.Metadata.ID
+		meta.SetExternalName(cr, existing.Metadata.ID)
+		return managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{}}, nil
+	case !codefreshclient.IsNotFound(err):
+		return managed.ExternalCreation{}, errors.Wrap(err, errLookingUpPipeline)
+	}
+
+	spec, err := c.resolveSpec(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errResolvingVariables)
+	}
+
 	// Set up the parameters for pipeline creation
 	params := v1alpha1.PipelineCreateParams{
 		Metadata: v1alpha1.PipelineMetadata{
-			Name: cr.Spec.ForProvider.Metadata.Name,
-		},
-		Spec: v1alpha1.PipelineSpecStruct{
-			Triggers:  cr.Spec.ForProvider.Spec.Triggers,
-			Steps:     cr.Spec.ForProvider.Spec.Steps,
-			Stages:    cr.Spec.ForProvider.Spec.Stages,
-			Variables: cr.Spec.ForProvider.Spec.Variables,
-			Options:   cr.Spec.ForProvider.Spec.Options,
-			// Contexts:  cr.Spec.ForProvider.Spec.Contexts,
+			Name:      cr.Spec.ForProvider.Metadata.Name,
+			Labels:    cr.Spec.ForProvider.Metadata.Labels,
+			Deprecate: cr.Spec.ForProvider.Metadata.Deprecate,
 		},
+		Spec: specRequest(spec, cr.GetStepOrder()),
 	}
 
-	// Response struct to hold the created pipeline's ID
-	var respData v1alpha1.CreatePipelineResponse
-
-	// Use the generic CreateResource method
-	if err := c.service.CreateResource(ctx, "pipelines", params, &respData); err != nil {
+	respData, err := c.service.Pipelines().Create(ctx, params)
+	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreatingPipeline)
 	}
 
-	// Store the pipeline ID in the status
+	// Store the pipeline ID in the status and the external name
 	cr.Status.AtProvider.ID = respData.Metadata.ID
+	cr.Status.AtProvider.Revision = respData.Metadata.Revision
+	cr.Status.AtProvider.AppliedRevision = respData.Metadata.Revision
 	/*	cr.Status.AtProvider.Name = respData.Metadata.Name */
+	meta.SetExternalName(cr, respData.Metadata.ID)
+	// The managed reconciler may discard status set here, in which case the
+	// next Observe finds encrypted variables outdated and Update records
+	// their hashes again.
+	cr.Status.AtProvider.VariableHashes = variableHashes(cr.GetUID(), spec.Variables)
+	cr.Status.AtProvider.TriggerVariableHashes = triggerVariableHashes(cr.GetUID(), spec.Triggers)
+	cr.Status.AtProvider.Owned = declaredEntries(cr)
 
-	// Update the status of the resource with the new pipeline ID
-	if err := c.client.Status().Update(ctx, cr); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errUpdatingPipeline)
+	// The pipeline exists now, so failing to annotate it must not fail the
+	// create. Update sets annotations that are missing once Observe finds
+	// them.
+	if err := c.syncAnnotations(ctx, cr, respData.Metadata.ID); err != nil {
+		c.logger.Debug("Cannot annotate new pipeline", "error", err, "pipelineID", respData.Metadata.ID)
 	}
 
 	return managed.ExternalCreation{
@@ -243,31 +1008,78 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotPipeline)
 	}
 
-	fmt.Printf("Updating: %+v", cr)
-
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(constants.ErrExpectedCodeFreshClient)
 	}
 
-	/*	updateParams := v1alpha1.PipelineCreateParams{
-			Metadata: v1alpha1.PipelineMetadata{
-				Name: cr.Spec.ForProvider.Metadata.Name,
-			},
-			Spec: v1alpha1.PipelineSpecStruct{
-				Triggers:  cr.Spec.ForProvider.Spec.Triggers,
-				Steps:     cr.Spec.ForProvider.Spec.Steps,
-				Stages:    cr.Spec.ForProvider.Spec.Stages,
-				Variables: cr.Spec.ForProvider.Spec.Variables,
-				Options:   cr.Spec.ForProvider.Spec.Options,
-				// Contexts:  cr.Spec.ForProvider.Spec.Contexts,
-			},
+	patch := codefreshclient.PipelinePatch{
+		Metadata: codefreshclient.PipelinePatchMetadata{
+			Name:      cr.Spec.ForProvider.Metadata.Name,
+			Labels:    cr.Spec.ForProvider.Metadata.Labels,
+			Deprecate: cr.Spec.ForProvider.Metadata.Deprecate,
+		},
+	}
+	rev := updateRevision(cr)
+	if rev > 0 {
+		patch.Metadata.Revision = &rev
+	}
+	spec, err := c.resolveSpec(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errResolvingVariables)
+	}
+	hashes := variableHashes(cr.GetUID(), spec.Variables)
+	triggerHashes := triggerVariableHashes(cr.GetUID(), spec.Triggers)
+	if isAdditive(cr) {
+		pipeline, err := c.service.Pipelines().Get(ctx, cr.Status.AtProvider.ID)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errGettingPipeline)
 		}
+		// CodeFresh only returns the masked value of encrypted variables, so
+		// those added elsewhere cannot be sent back. The variables are then
+		// left out of the patch if they are up to date, and not updated at
+		// all otherwise.
+		if foreign := foreignSecrets(cr, pipeline); spec.Variables != nil && len(foreign) > 0 {
+			if len(variablesDrift(cr.Spec.ForProvider.Spec.Variables, spec.Variables, managedEntries(cr, pipeline).Spec.Variables, cr.Status.AtProvider.VariableHashes, cr.GetUID())) > 0 {
+				err := errors.Errorf(errFmtForeignSecrets, strings.Join(foreign, ", "))
+				c.recorder.Event(cr, event.Warning(reasonVariablesUpdateRefused, err))
+				return managed.ExternalUpdate{}, err
+			}
+			spec.Variables = nil
+		}
+		patch.Metadata.Labels = mergeEntries(cr, spec, patch.Metadata.Labels, pipeline)
+	}
+	// Spec fields that are unset are left unchanged by the patch.
+	req := specRequest(spec, cr.GetStepOrder())
+	patch.Spec = &req
+	updated, err := c.service.Pipelines().Patch(ctx, cr.Status.AtProvider.ID, patch)
+	if codefreshclient.IsConflict(err) {
+		// Someone changed the pipeline since the provider last applied it.
+		// Rather than overwrite their change, the applied revision is kept,
+		// so later updates conflict too until the change is accepted.
+		cr.Status.AtProvider.ConflictGeneration = cr.GetGeneration()
+		cr.SetConditions(v1alpha1.ConflictDetected().WithMessage(fmt.Sprintf(msgFmtConflict, rev, cr.Status.AtProvider.Revision, v1alpha1.AnnotationKeyAcceptRevision)))
+		return managed.ExternalUpdate{}, errors.Wrap(err, errPipelineConflict)
+	}
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatingPipeline)
+	}
+	cr.Status.AtProvider.Revision = updated.Metadata.Revision
+	cr.Status.AtProvider.AppliedRevision = updated.Metadata.Revision
+	cr.Status.AtProvider.ConflictGeneration = 0
+	if cr.GetCondition(v1alpha1.TypeConflictDetected).Status == corev1.ConditionTrue {
+		cr.SetConditions(v1alpha1.NoConflict())
+	}
+	if spec.Variables != nil {
+		cr.Status.AtProvider.VariableHashes = hashes
+	}
+	if spec.Triggers != nil {
+		cr.Status.AtProvider.TriggerVariableHashes = triggerHashes
+	}
+	cr.Status.AtProvider.Owned = declaredEntries(cr)
 
-		// Update the resource
-		err := c.service.UpdateResource(ctx, "pipelines", cr.Status.AtProvider.ID, updateParams, nil)
-		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatingPipeline)
-		}*/
+	if err := c.syncAnnotations(ctx, cr, cr.Status.AtProvider.ID); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
 
 	return managed.ExternalUpdate{}, nil
 }
@@ -279,8 +1091,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 
 	// Delete the resource
-	err := c.service.DeleteResource(ctx, "pipelines", cr.Status.AtProvider.ID)
-	if err != nil {
+	if err := c.service.Pipelines().Delete(ctx, cr.Status.AtProvider.ID); err != nil {
 		return errors.Wrap(err, errDeletingPipeline)
 	}
 
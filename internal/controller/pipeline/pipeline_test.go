@@ -18,11 +18,26 @@ package pipeline
 
 import (
 	"context"
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
 	"crossplane-provider-codefresh/internal/client"
+	"crossplane-provider-codefresh/internal/client/fake"
+	"crossplane-provider-codefresh/internal/helpers"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -57,14 +72,1059 @@ func TestObserve(t *testing.T) {
 		setup  func(*client.MockCodeFreshAPIClient)
 		want   want
 	}{
-		// TODO: Add test cases.
+		"PipelineNotCreated": {
+			reason: "Should return ResourceExists false when no pipeline ID is recorded.",
+			args: args{
+				ctx: context.TODO(),
+				mg:  &v1alpha1.Pipeline{},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"PipelineDefaultsLateInitialized": {
+			reason: "Should late-initialize the stages and options CodeFresh defaulted.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:  []string{"clone", "build", "test"},
+						Options: &v1alpha1.PipelineOptions{EnableNotifications: true},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+					ConnectionDetails:       managed.ConnectionDetails{},
+				},
+			},
+		},
+		"PipelineAlreadyInitialized": {
+			reason: "Should not report late-initialization when all optional fields are set, but should report options and stages that differ as drift.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:  []string{"clone", "build", "test"},
+						Options: &v1alpha1.PipelineOptions{EnableNotifications: true},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"LabelsOutdated": {
+			reason: "Should report the pipeline outdated when its labels differ from the spec.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{
+								Name:   "project/pipeline",
+								Labels: map[string][]string{"tags": {"ci", "release"}},
+							},
+							Spec: v1alpha1.PipelineSpecStruct{Stages: []string{"build"}, Options: &v1alpha1.PipelineOptions{}},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{
+						Name:   "project/pipeline",
+						ID:     "pipeline-id",
+						Labels: map[string][]string{"tags": {"ci"}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"StepsReordered": {
+			reason: "Should report the pipeline outdated when CodeFresh runs its steps in another order.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Steps:   map[string]v1alpha1.PipelineStep{"build": {}, "test": {}},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						Steps:  v1alpha1.PipelineStepsResponse{{Name: "test"}, {Name: "build"}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"StepValuesOutdated": {
+			reason: "Should report the pipeline outdated when a step differs from the spec in more than its name.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Steps:   map[string]v1alpha1.PipelineStep{"build": {Values: []v1alpha1.KeyValue{{Key: "image", Value: "node:20"}}}},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						Steps:  v1alpha1.PipelineStepsResponse{{Name: "build", Values: []v1alpha1.KeyValue{{Key: "image", Value: "node:18"}}}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"StepValuesUpToDate": {
+			reason: "Should ignore the order of the values of a step.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Steps: map[string]v1alpha1.PipelineStep{"build": {Values: []v1alpha1.KeyValue{
+									{Key: "image", Value: "node:20"},
+									{Key: "stage", Value: "build"},
+								}}},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						Steps: v1alpha1.PipelineStepsResponse{{Name: "build", Values: []v1alpha1.KeyValue{
+							{Key: "stage", Value: "build"},
+							{Key: "image", Value: "node:20"},
+						}}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"StagesReordered": {
+			reason: "Should report the pipeline outdated when CodeFresh runs its stages in another order.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build", "test"},
+								Options: &v1alpha1.PipelineOptions{},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"test", "build"},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"OptionsOutdated": {
+			reason: "Should report the pipeline outdated when its options differ from the spec.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{NoCache: true},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:  []string{"build"},
+						Options: &v1alpha1.PipelineOptions{},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"TriggerSettingsOutdated": {
+			reason: "Should report the pipeline outdated when a trigger differs from the spec in more than its name, in any management mode.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:   []string{"build"},
+								Options:  &v1alpha1.PipelineOptions{},
+								Triggers: []v1alpha1.PipelineTrigger{{Name: "push", Events: []string{"push"}}},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:   []string{"build"},
+						Triggers: []v1alpha1.PipelineTrigger{{Name: "push", Events: []string{"push", "pullrequest.opened"}}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"TriggerVariableOutdated": {
+			reason: "Should report the pipeline outdated when a variable of a trigger differs from the spec.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:   []string{"build"},
+								Options:  &v1alpha1.PipelineOptions{},
+								Triggers: []v1alpha1.PipelineTrigger{{Name: "push", Variables: []v1alpha1.PipelineVariable{{Key: "ENV", Value: "dev"}}}},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:   []string{"build"},
+						Triggers: []v1alpha1.PipelineTrigger{{Name: "push", Variables: []v1alpha1.PipelineVariable{{Key: "ENV", Value: "prod"}}}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"TriggersUpToDate": {
+			reason: "Should ignore differences in triggers CodeFresh ignores, such as their order, how their regexes are written and the masked values of encrypted variables.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{UID: "uid"},
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Triggers: []v1alpha1.PipelineTrigger{
+									{Name: "push", BranchRegex: "^dev-.*", CommentRegex: "/skip/gi", Events: []string{"push", "pullrequest.opened"}},
+									{Name: "nightly", Variables: []v1alpha1.PipelineVariable{{Key: "TOKEN", Value: "secret", Encrypted: true}, {Key: "ENV", Value: "dev"}}},
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{
+							ID:                    "pipeline-id",
+							TriggerVariableHashes: map[string]string{"nightly/TOKEN": helpers.VariableHash("uid", "nightly/TOKEN", "secret")},
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						Triggers: []v1alpha1.PipelineTrigger{
+							{Name: "nightly", Variables: []v1alpha1.PipelineVariable{{Key: "ENV", Value: "dev"}, {Key: "TOKEN", Value: "*****", Encrypted: true}}},
+							{Name: "push", BranchRegex: "/^dev-.*/", CommentRegex: "/skip/ig", Events: []string{"pullrequest.opened", "push"}},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"TriggerEncryptedVariableOutdated": {
+			reason: "Should report the pipeline outdated when the value of an encrypted trigger variable differs from the one last sent.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{UID: "uid"},
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Triggers: []v1alpha1.PipelineTrigger{
+									{Name: "push", BranchRegex: "^dev-.*", CommentRegex: "/skip/gi", Events: []string{"push", "pullrequest.opened"}},
+									{Name: "nightly", Variables: []v1alpha1.PipelineVariable{{Key: "TOKEN", Value: "secret", Encrypted: true}, {Key: "ENV", Value: "dev"}}},
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{
+							ID:                    "pipeline-id",
+							TriggerVariableHashes: map[string]string{"nightly/TOKEN": helpers.VariableHash("uid", "nightly/TOKEN", "old-secret")},
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						Triggers: []v1alpha1.PipelineTrigger{
+							{Name: "nightly", Variables: []v1alpha1.PipelineVariable{{Key: "ENV", Value: "dev"}, {Key: "TOKEN", Value: "*****", Encrypted: true}}},
+							{Name: "push", BranchRegex: "/^dev-.*/", CommentRegex: "/skip/ig", Events: []string{"pullrequest.opened", "push"}},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"TriggerRemovedOutdated": {
+			reason: "Should report the pipeline outdated when CodeFresh has a trigger the spec does not declare.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:   []string{"build"},
+								Options:  &v1alpha1.PipelineOptions{},
+								Triggers: []v1alpha1.PipelineTrigger{{Name: "push"}},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:   []string{"build"},
+						Triggers: []v1alpha1.PipelineTrigger{{Name: "push"}, {Name: "ui"}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ConcurrencyOutdated": {
+			reason: "Should report the pipeline outdated when a concurrency limit differs from the spec.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:            []string{"build"},
+								Options:           &v1alpha1.PipelineOptions{},
+								Concurrency:       pointer.Int(2),
+								BranchConcurrency: pointer.Int(1),
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:      []string{"build"},
+						Concurrency: pointer.Int(2),
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"TerminationPolicyOutdated": {
+			reason: "Should report the pipeline outdated when CodeFresh has other termination rules than the policy.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:            []string{"build"},
+								Options:           &v1alpha1.PipelineOptions{},
+								TerminationPolicy: &v1alpha1.PipelineTerminationPolicy{TerminateChildBuilds: true},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						TerminationPolicy: []v1alpha1.PipelineTerminationRule{
+							{Type: v1alpha1.TerminationRuleTypeAnnotation, Event: v1alpha1.TerminationRuleEventOnTerminate, Key: v1alpha1.TerminationRuleKeyPredecessor},
+							{Type: v1alpha1.TerminationRuleTypeBranch, Event: v1alpha1.TerminationRuleEventOnCreate},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ConcurrencyAndTerminationUpToDate": {
+			reason: "Should ignore the order of termination rules and unset concurrency limits.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:   []string{"build"},
+								Options:  &v1alpha1.PipelineOptions{},
+								Priority: pointer.Int(-5),
+								TerminationPolicy: &v1alpha1.PipelineTerminationPolicy{
+									TerminatePreviousBuilds: &v1alpha1.TerminatePreviousBuilds{IgnoreTrigger: true},
+									TerminateChildBuilds:    true,
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:             []string{"build"},
+						Priority:           pointer.Int(-5),
+						TriggerConcurrency: pointer.Int(3),
+						TerminationPolicy: []v1alpha1.PipelineTerminationRule{
+							{Type: v1alpha1.TerminationRuleTypeAnnotation, Event: v1alpha1.TerminationRuleEventOnTerminate, Key: v1alpha1.TerminationRuleKeyPredecessor},
+							{Type: v1alpha1.TerminationRuleTypeBranch, Event: v1alpha1.TerminationRuleEventOnCreate, IgnoreTrigger: true},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"NormalizedUpToDate": {
+			reason: "Should ignore differences CodeFresh ignores, such as the order of tags and how a branch regex is written.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{
+								Name:   "project/pipeline",
+								Labels: map[string][]string{"tags": {"release", "ci"}, "empty": {}},
+							},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								TerminationPolicy: &v1alpha1.PipelineTerminationPolicy{
+									TerminatePreviousBuilds: &v1alpha1.TerminatePreviousBuilds{BranchName: "/^dev/gi"},
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{
+						Name:   "project/pipeline",
+						ID:     "pipeline-id",
+						Labels: map[string][]string{"tags": {"ci", "release"}},
+					},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						TerminationPolicy: []v1alpha1.PipelineTerminationRule{
+							{Type: v1alpha1.TerminationRuleTypeBranch, Event: v1alpha1.TerminationRuleEventOnCreate, BranchName: "/^dev/ig"},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"SpecTemplateUpToDate": {
+			reason: "Should compare the location of a spec template rather than the steps loaded from it.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:       []string{"build"},
+								Options:      &v1alpha1.PipelineOptions{},
+								SpecTemplate: &v1alpha1.PipelineSpecTemplate{Repo: "org/app", Path: "./codefresh.yml", Revision: "main"},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:       []string{"build"},
+						SpecTemplate: &v1alpha1.PipelineSpecTemplate{Location: "git", Repo: "org/app", Path: "./codefresh.yml", Revision: "main"},
+						Steps:        v1alpha1.PipelineStepsResponse{{Name: "build"}, {Name: "test"}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"SpecTemplateOutdated": {
+			reason: "Should report the pipeline outdated when the spec template is loaded from another revision.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:       []string{"build"},
+								Options:      &v1alpha1.PipelineOptions{},
+								SpecTemplate: &v1alpha1.PipelineSpecTemplate{Repo: "org/app", Path: "./codefresh.yml", Revision: "main"},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:       []string{"build"},
+						SpecTemplate: &v1alpha1.PipelineSpecTemplate{Location: "git", Repo: "org/app", Path: "./codefresh.yml", Revision: "develop"},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ExternalResourcesUpToDate": {
+			reason: "Should default the type of external resources to git when comparing them.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								ExternalResources: []v1alpha1.PipelineExternalResource{
+									{Repo: "org/config", Revision: "main", Source: "ci/a.yml", Destination: "a.yml"},
+									{Repo: "org/config", Revision: "main", Source: "ci/b.yml", Destination: "b.yml"},
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						ExternalResources: []v1alpha1.PipelineExternalResource{
+							{Type: "git", Repo: "org/config", Revision: "main", Source: "ci/a.yml", Destination: "a.yml"},
+							{Type: "git", Repo: "org/config", Revision: "main", Source: "ci/b.yml", Destination: "b.yml"},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ExternalResourcesReordered": {
+			reason: "Should report the pipeline outdated when CodeFresh copies the external resources in another order.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								ExternalResources: []v1alpha1.PipelineExternalResource{
+									{Repo: "org/config", Revision: "main", Source: "ci/a.yml", Destination: "a.yml"},
+									{Repo: "org/config", Revision: "main", Source: "ci/b.yml", Destination: "b.yml"},
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						ExternalResources: []v1alpha1.PipelineExternalResource{
+							{Type: "git", Repo: "org/config", Revision: "main", Source: "ci/b.yml", Destination: "b.yml"},
+							{Type: "git", Repo: "org/config", Revision: "main", Source: "ci/a.yml", Destination: "a.yml"},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"EncryptedVariableUpToDate": {
+			reason: "Should compare an encrypted variable, which CodeFresh masks, by the hash of the value last sent.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{UID: "uid"},
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Variables: []v1alpha1.PipelineVariable{
+									{Key: "TOKEN", Value: "secret-1", Encrypted: true},
+									{Key: "REGION", Value: "eu-west-1"},
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{
+							ID:             "pipeline-id",
+							VariableHashes: map[string]string{"TOKEN": helpers.VariableHash("uid", "TOKEN", "secret-1")},
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						Variables: []v1alpha1.PipelineVariable{
+							{Key: "REGION", Value: "eu-west-1"},
+							{Key: "TOKEN", Value: "*****", Encrypted: true},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"EncryptedVariableChanged": {
+			reason: "Should report the pipeline outdated when the value of an encrypted variable no longer matches the hash last sent.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{UID: "uid"},
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Variables: []v1alpha1.PipelineVariable{
+									{Key: "TOKEN", Value: "secret-2", Encrypted: true},
+									{Key: "REGION", Value: "eu-west-1"},
+								},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{
+							ID:             "pipeline-id",
+							VariableHashes: map[string]string{"TOKEN": helpers.VariableHash("uid", "TOKEN", "secret-1")},
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages: []string{"build"},
+						Variables: []v1alpha1.PipelineVariable{
+							{Key: "REGION", Value: "eu-west-1"},
+							{Key: "TOKEN", Value: "*****", Encrypted: true},
+						},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"InlineStepsFromSpecTemplate": {
+			reason: "Should report the pipeline outdated when inline steps are wanted but CodeFresh loads them from a spec template.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec: v1alpha1.PipelineSpecStruct{
+								Stages:  []string{"build"},
+								Options: &v1alpha1.PipelineOptions{},
+								Steps:   map[string]v1alpha1.PipelineStep{"build": {}},
+							},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec: v1alpha1.PipelineSpecResponse{
+						Stages:       []string{"build"},
+						SpecTemplate: &v1alpha1.PipelineSpecTemplate{Location: "git", Repo: "org/app", Revision: "main"},
+						Steps:        v1alpha1.PipelineStepsResponse{{Name: "build"}},
+					},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"MirroredLabelMissing": {
+			reason: "Should report the pipeline outdated when a mirrored label of the Pipeline is not annotated in CodeFresh.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "payments", "ignored": "x"}},
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata:     v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+							Spec:         v1alpha1.PipelineSpecStruct{Stages: []string{"build"}, Options: &v1alpha1.PipelineOptions{}},
+							MirrorLabels: []string{"team", "cost-center"},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+				}
+				m.MockAnnotations.MockListResponse = []v1alpha1.Annotation{{Key: "team", Value: "platform"}}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"AnnotationsUpToDate": {
+			reason: "Should ignore annotations in CodeFresh that are not managed by the Pipeline.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "payments"}},
+					Spec: v1alpha1.PipelineSpec{
+						ForProvider: v1alpha1.PipelineParameters{
+							Metadata: v1alpha1.PipelineMetadata{
+								Name:        "project/pipeline",
+								Annotations: map[string]string{"cost-center": "42"},
+							},
+							Spec:         v1alpha1.PipelineSpecStruct{Stages: []string{"build"}, Options: &v1alpha1.PipelineOptions{}},
+							MirrorLabels: []string{"team"},
+						},
+					},
+					Status: v1alpha1.PipelineStatus{
+						AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+					Spec:     v1alpha1.PipelineSpecResponse{Stages: []string{"build"}},
+				}
+				m.MockAnnotations.MockListResponse = []v1alpha1.Annotation{
+					{Key: "cost-center", Value: "42"},
+					{Key: "team", Value: "payments"},
+					{Key: "other", Value: "kept"},
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			mockClient := &client.MockCodeFreshAPIClient{}
 			tc.setup(mockClient)
-			e := external{service: mockClient}
+			e := external{service: mockClient, logger: logging.NewNopLogger(), recorder: event.NewNopRecorder()}
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -75,3 +1135,942 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestObserveDeprecation(t *testing.T) {
+	deprecatedMsg := "CodeFresh reports the pipeline deprecated: applicationPort=8080, repoPipeline=true"
+
+	cases := map[string]struct {
+		reason     string
+		spec       *v1alpha1.PipelineDeprecate
+		conditions []xpv1.Condition
+		observed   *v1alpha1.PipelineDeprecate
+		upToDate   bool
+		want       *xpv1.Condition
+	}{
+		"NotDeprecated": {
+			reason:   "Should not add a Deprecated condition to a pipeline that was never deprecated.",
+			upToDate: true,
+		},
+		"Deprecated": {
+			reason:   "Should set the Deprecated condition when CodeFresh reports the pipeline deprecated.",
+			observed: &v1alpha1.PipelineDeprecate{ApplicationPort: pointer.String("8080"), RepoPipeline: pointer.Bool(true)},
+			upToDate: true,
+			want:     condition(v1alpha1.Deprecated().WithMessage(deprecatedMsg)),
+		},
+		"NoLongerDeprecated": {
+			reason:     "Should set the Deprecated condition false once CodeFresh no longer reports the pipeline deprecated.",
+			conditions: []xpv1.Condition{v1alpha1.Deprecated()},
+			upToDate:   true,
+			want:       condition(v1alpha1.NotDeprecated()),
+		},
+		"DeprecateOutdated": {
+			reason:   "Should report the pipeline outdated when a deprecated setting of the spec differs.",
+			spec:     &v1alpha1.PipelineDeprecate{RepoPipeline: pointer.Bool(true)},
+			observed: &v1alpha1.PipelineDeprecate{ApplicationPort: pointer.String("8080")},
+			want:     condition(v1alpha1.Deprecated().WithMessage("CodeFresh reports the pipeline deprecated: applicationPort=8080")),
+		},
+		"DeprecateUpToDate": {
+			reason:   "Should ignore deprecated settings that are not in the spec.",
+			spec:     &v1alpha1.PipelineDeprecate{RepoPipeline: pointer.Bool(true)},
+			observed: &v1alpha1.PipelineDeprecate{ApplicationPort: pointer.String("8080"), RepoPipeline: pointer.Bool(true)},
+			upToDate: true,
+			want:     condition(v1alpha1.Deprecated().WithMessage(deprecatedMsg)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &client.MockCodeFreshAPIClient{}
+			mockClient.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+				Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id", Deprecate: tc.observed},
+				Spec:     v1alpha1.PipelineSpecResponse{Stages: []string{"build"}},
+			}
+			e := external{service: mockClient, logger: logging.NewNopLogger(), recorder: event.NewNopRecorder()}
+			cr := &v1alpha1.Pipeline{
+				Spec: v1alpha1.PipelineSpec{
+					ForProvider: v1alpha1.PipelineParameters{
+						Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline", Deprecate: tc.spec},
+						Spec:     v1alpha1.PipelineSpecStruct{Stages: []string{"build"}, Options: &v1alpha1.PipelineOptions{}},
+					},
+				},
+				Status: v1alpha1.PipelineStatus{
+					AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id"},
+				},
+			}
+			cr.SetConditions(tc.conditions...)
+			got, err := e.Observe(context.TODO(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if got.ResourceUpToDate != tc.upToDate {
+				t.Errorf("\n%s\ne.Observe(...): want ResourceUpToDate %t, got %t", tc.reason, tc.upToDate, got.ResourceUpToDate)
+			}
+			var c *xpv1.Condition
+			if got := cr.GetCondition(v1alpha1.TypeDeprecated); got.Status != corev1.ConditionUnknown {
+				c = &got
+			}
+			if diff := cmp.Diff(tc.want, c, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want Deprecated condition, +got Deprecated condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveDrift(t *testing.T) {
+	drifted := `metadata.annotations[cost-center]: want "42", got <unset>; spec.concurrency: want 2, got 1`
+
+	cases := map[string]struct {
+		reason     string
+		drift      string
+		conditions []xpv1.Condition
+		observed   *int
+		wantDrift  string
+		want       *xpv1.Condition
+		events     []event.Event
+	}{
+		"NeverDrifted": {
+			reason:   "Should not add a Drifted condition to a pipeline that never drifted.",
+			observed: pointer.Int(2),
+		},
+		"Drifted": {
+			reason:    "Should record and report how the pipeline differs from the spec.",
+			wantDrift: drifted,
+			want:      condition(v1alpha1.Drifted().WithMessage(drifted)),
+			events:    []event.Event{event.Normal(reasonDriftDetected, drifted)},
+		},
+		"StillDrifted": {
+			reason:     "Should not report drift that was already reported again.",
+			drift:      drifted,
+			conditions: []xpv1.Condition{v1alpha1.Drifted().WithMessage(drifted)},
+			wantDrift:  drifted,
+			want:       condition(v1alpha1.Drifted().WithMessage(drifted)),
+		},
+		"NoLongerDrifted": {
+			reason:     "Should clear the drift once the pipeline is up to date.",
+			drift:      drifted,
+			conditions: []xpv1.Condition{v1alpha1.Drifted().WithMessage(drifted)},
+			observed:   pointer.Int(2),
+			want:       condition(v1alpha1.NotDrifted()),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &client.MockCodeFreshAPIClient{}
+			mockClient.MockPipelines.MockGetResponse = &v1alpha1.PipelineDocument{
+				Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline", ID: "pipeline-id"},
+				Spec:     v1alpha1.PipelineSpecResponse{Stages: []string{"build"}, Concurrency: pointer.Int(1)},
+			}
+			if tc.observed != nil {
+				mockClient.MockPipelines.MockGetResponse.Spec.Concurrency = tc.observed
+				mockClient.MockAnnotations.MockListResponse = []v1alpha1.Annotation{{Key: "cost-center", Value: "42"}}
+			}
+			r := &recorder{}
+			e := external{service: mockClient, logger: logging.NewNopLogger(), recorder: r}
+			cr := &v1alpha1.Pipeline{
+				Spec: v1alpha1.PipelineSpec{
+					ForProvider: v1alpha1.PipelineParameters{
+						Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline", Annotations: map[string]string{"cost-center": "42"}},
+						Spec:     v1alpha1.PipelineSpecStruct{Stages: []string{"build"}, Options: &v1alpha1.PipelineOptions{}, Concurrency: pointer.Int(2)},
+					},
+				},
+				Status: v1alpha1.PipelineStatus{
+					AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id", Drift: tc.drift},
+				},
+			}
+			cr.SetConditions(tc.conditions...)
+			if _, err := e.Observe(context.TODO(), cr); err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.wantDrift, cr.Status.AtProvider.Drift); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want drift, +got drift:\n%s", tc.reason, diff)
+			}
+			var c *xpv1.Condition
+			if got := cr.GetCondition(v1alpha1.TypeDrifted); got.Status != corev1.ConditionUnknown {
+				c = &got
+			}
+			if diff := cmp.Diff(tc.want, c, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want Drifted condition, +got Drifted condition:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// recorder records the events it is sent.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
+func condition(c xpv1.Condition) *xpv1.Condition {
+	return &c
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		id  string
+		err error
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		setup  func(*client.MockCodeFreshAPIClient)
+		want   want
+	}{
+		"AdoptExisting": {
+			reason: "Should adopt a pipeline that already exists with the same name instead of creating another.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetByNameResponse = &v1alpha1.PipelineDocument{
+					Metadata: v1alpha1.PipelineMetadataResponse{ID: "existing-pipeline", Name: "project/pipeline"},
+				}
+				m.MockPipelines.MockCreateErr = errors.New("should not be called")
+			},
+			want: want{id: "existing-pipeline"},
+		},
+		"CreateNew": {
+			reason: "Should create the pipeline when none exists with the same name.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetByNameErr = client.ErrResourceNotFound
+				m.MockPipelines.MockCreateResponse = &v1alpha1.CreatePipelineResponse{}
+				m.MockPipelines.MockCreateResponse.Metadata.ID = "new-pipeline"
+			},
+			want: want{id: "new-pipeline"},
+		},
+		"LookupFailed": {
+			reason: "Should not create the pipeline when the lookup by name fails for another reason.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockGetByNameErr = errBoom
+			},
+			want: want{err: errors.Wrap(errBoom, errLookingUpPipeline)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &client.MockCodeFreshAPIClient{}
+			tc.setup(mockClient)
+			e := external{service: mockClient, logger: logging.NewNopLogger(), recorder: event.NewNopRecorder()}
+			cr := &v1alpha1.Pipeline{
+				Spec: v1alpha1.PipelineSpec{
+					ForProvider: v1alpha1.PipelineParameters{
+						Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+					},
+				},
+			}
+			_, err := e.Create(context.TODO(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.id, meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, cr.Status.AtProvider.ID); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want pipeline ID, +got pipeline ID:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason  string
+		owned   []string
+		setup   func(*client.MockCodeFreshAPIClient)
+		want    []v1alpha1.Annotation
+		deleted []string
+		err     error
+	}{
+		"SetOutdatedAnnotations": {
+			reason: "Should set only the annotations that are missing or differ, with explicit annotations overriding mirrored labels.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockAnnotations.MockListResponse = []v1alpha1.Annotation{{Key: "owner", Value: "alice"}}
+			},
+			want: []v1alpha1.Annotation{
+				{EntityType: v1alpha1.AnnotationEntityPipeline, EntityID: "pipeline-id", Key: "cost-center", Value: "42"},
+				{EntityType: v1alpha1.AnnotationEntityPipeline, EntityID: "pipeline-id", Key: "team", Value: "override"},
+			},
+		},
+		"DeleteStaleAnnotations": {
+			reason: "Should delete the owned annotations that are no longer wanted, and leave others alone.",
+			owned:  []string{"cost-center", "owner", "region", "team"},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockAnnotations.MockListResponse = []v1alpha1.Annotation{
+					{Key: "cost-center", Value: "42"}, {Key: "owner", Value: "alice"}, {Key: "team", Value: "override"},
+					{Key: "region", Value: "eu"}, {Key: "manual", Value: "x"},
+				}
+			},
+			deleted: []string{"region"},
+		},
+		"PatchFailed": {
+			reason: "Should return an error when the pipeline cannot be patched.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockPatchErr = errBoom
+			},
+			err: errors.Wrap(errBoom, errUpdatingPipeline),
+		},
+		"Conflict": {
+			reason: "Should neither overwrite the pipeline nor set annotations when it changed since it was last applied.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockPipelines.MockPatchErr = client.ErrConflict
+			},
+			err: errors.Wrap(client.ErrConflict, errPipelineConflict),
+		},
+		"SetFailed": {
+			reason: "Should return an error when an annotation cannot be set.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockAnnotations.MockSetErr = errBoom
+			},
+			want: []v1alpha1.Annotation{
+				{EntityType: v1alpha1.AnnotationEntityPipeline, EntityID: "pipeline-id", Key: "cost-center", Value: "42"},
+			},
+			err: errors.Wrap(errBoom, errSettingAnnotation),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &client.MockCodeFreshAPIClient{}
+			tc.setup(mockClient)
+			e := external{service: mockClient, logger: logging.NewNopLogger(), recorder: event.NewNopRecorder()}
+			cr := &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cost-center": "42", "owner": "alice", "team": "payments"}},
+				Spec: v1alpha1.PipelineSpec{
+					ForProvider: v1alpha1.PipelineParameters{
+						Metadata: v1alpha1.PipelineMetadata{
+							Name:        "project/pipeline",
+							Annotations: map[string]string{"team": "override"},
+						},
+						MirrorLabels: []string{"cost-center", "owner", "team"},
+					},
+				},
+				Status: v1alpha1.PipelineStatus{
+					AtProvider: v1alpha1.PipelineObservation{ID: "pipeline-id", AnnotationKeys: tc.owned},
+				},
+			}
+			_, err := e.Update(context.TODO(), cr)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, mockClient.MockAnnotations.SetAnnotations); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want annotations, +got annotations:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.deleted, mockClient.MockAnnotations.DeletedKeys); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want deleted annotations, +got deleted annotations:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAnnotationsAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	e := external{
+		client:   &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "payments"}},
+		Spec: v1alpha1.PipelineSpec{
+			ForProvider: v1alpha1.PipelineParameters{
+				Metadata:     v1alpha1.PipelineMetadata{Name: "project/pipeline", Annotations: map[string]string{"cost-center": "42"}},
+				MirrorLabels: []string{"team"},
+			},
+		},
+	}
+	ctx := context.Background()
+	annotations := func() map[string]string {
+		return srv.Annotations(v1alpha1.AnnotationEntityPipeline, cr.Status.AtProvider.ID)
+	}
+
+	// A new pipeline is annotated when it is created.
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"cost-center": "42", "team": "payments"}, annotations()); diff != "" {
+		t.Errorf("e.Create(...): -want annotations, +got annotations:\n%s", diff)
+	}
+
+	// The managed reconciler may discard status set by Create, so Observe
+	// records the annotations the provider owns.
+	cr.Status.AtProvider.AnnotationKeys = nil
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+	if diff := cmp.Diff([]string{"cost-center", "team"}, cr.Status.AtProvider.AnnotationKeys); diff != "" {
+		t.Errorf("e.Observe(...): -want owned annotations, +got owned annotations:\n%s", diff)
+	}
+
+	// Removing a mirrored label deletes its annotation, leaving annotations
+	// set elsewhere in place.
+	if err := e.service.Annotations().Set(ctx, v1alpha1.Annotation{EntityType: v1alpha1.AnnotationEntityPipeline, EntityID: cr.Status.AtProvider.ID, Key: "manual", Value: "x"}); err != nil {
+		t.Fatalf("Set(...): %v", err)
+	}
+	cr.SetLabels(nil)
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if want := `metadata.annotations[team]: want <unset>, got "payments"`; cr.Status.AtProvider.Drift != want {
+		t.Errorf("e.Observe(...): want drift %q, got %q", want, cr.Status.AtProvider.Drift)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"cost-center": "42", "manual": "x"}, annotations()); diff != "" {
+		t.Errorf("e.Update(...): -want annotations, +got annotations:\n%s", diff)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+	if diff := cmp.Diff([]string{"cost-center"}, cr.Status.AtProvider.AnnotationKeys); diff != "" {
+		t.Errorf("e.Observe(...): -want owned annotations, +got owned annotations:\n%s", diff)
+	}
+}
+
+func TestLifecycleAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	e := external{
+		client:   &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Pipeline{
+		Spec: v1alpha1.PipelineSpec{
+			ForProvider: v1alpha1.PipelineParameters{
+				Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	if _, ok := srv.Pipeline(cr.Status.AtProvider.ID); !ok {
+		t.Fatalf("e.Create(...): pipeline %q not created", cr.Status.AtProvider.ID)
+	}
+
+	// A retried create, for example after the ID failed to persist, adopts
+	// the pipeline rather than creating a duplicate.
+	retry := cr.DeepCopy()
+	retry.Status.AtProvider.ID = ""
+	if _, err := e.Create(ctx, retry); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	if retry.Status.AtProvider.ID != cr.Status.AtProvider.ID {
+		t.Errorf("e.Create(...): want adopted pipeline %q, got %q", cr.Status.AtProvider.ID, retry.Status.AtProvider.ID)
+	}
+	if list, err := e.service.Pipelines().List(ctx, client.PipelineListOptions{}); err != nil || list.Count != 1 {
+		t.Errorf("e.Create(...): want 1 pipeline, got %+v (%v)", list, err)
+	}
+
+	got, err := e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	want := managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        true,
+		ResourceLateInitialized: true,
+		ConnectionDetails:       managed.ConnectionDetails{},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got:\n%s", diff)
+	}
+
+	// Steps keep their order through Create, and a reordering is restored
+	// by Update.
+	cr.Spec.ForProvider.Spec.Steps = map[string]v1alpha1.PipelineStep{"build": {}, "deploy": {}, "test": {}}
+	cr.SetStepOrder([]string{"test", "build", "deploy"})
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	p, _ := srv.Pipeline(cr.Status.AtProvider.ID)
+	if diff := cmp.Diff([]string{"test", "build", "deploy"}, stepNames(responseSteps(p.Spec.Steps))); diff != "" {
+		t.Errorf("e.Update(...): -want steps, +got steps:\n%s", diff)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+
+	// Switching to a spec template replaces the inline steps, and back.
+	cr.Spec.ForProvider.Spec.Steps = nil
+	cr.Spec.ForProvider.Spec.SpecTemplate = &v1alpha1.PipelineSpecTemplate{Repo: "org/app", Path: "./codefresh.yml", Revision: "main"}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	p, _ = srv.Pipeline(cr.Status.AtProvider.ID)
+	if p.Spec.SpecTemplate == nil || len(p.Spec.Steps) != 0 {
+		t.Errorf("e.Update(...): want spec template without inline steps, got %+v", p.Spec)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+	cr.Spec.ForProvider.Spec.SpecTemplate = nil
+	cr.Spec.ForProvider.Spec.Steps = map[string]v1alpha1.PipelineStep{"build": {}, "deploy": {}, "test": {}}
+	cr.SetStepOrder([]string{"test", "build", "deploy"})
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+
+	// Concurrency limits and the termination policy are synced by Update,
+	// leaving the steps it does not send in place.
+	cr.Spec.ForProvider.Spec.Concurrency = pointer.Int(1)
+	cr.Spec.ForProvider.Spec.TerminationPolicy = &v1alpha1.PipelineTerminationPolicy{
+		TerminatePreviousBuilds: &v1alpha1.TerminatePreviousBuilds{},
+	}
+	steps := cr.Spec.ForProvider.Spec.Steps
+	cr.Spec.ForProvider.Spec.Steps = nil
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	p, _ = srv.Pipeline(cr.Status.AtProvider.ID)
+	if p.Spec.Concurrency == nil || *p.Spec.Concurrency != 1 || len(p.Spec.TerminationPolicy) != 1 || len(p.Spec.Steps) != len(steps) {
+		t.Errorf("e.Update(...): want concurrency 1, one termination rule and unchanged steps, got %+v", p.Spec)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+
+	// An empty termination policy clears the rules.
+	cr.Spec.ForProvider.Spec.TerminationPolicy = &v1alpha1.PipelineTerminationPolicy{}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+
+	// External resources are synced by Update, and an empty list clears them.
+	cr.Spec.ForProvider.Spec.ExternalResources = []v1alpha1.PipelineExternalResource{
+		{Repo: "org/config", Revision: "main", Source: "ci/settings.yml", Destination: "settings.yml"},
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if p, _ = srv.Pipeline(cr.Status.AtProvider.ID); len(p.Spec.ExternalResources) != 1 {
+		t.Errorf("e.Update(...): want one external resource, got %+v", p.Spec.ExternalResources)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+	cr.Spec.ForProvider.Spec.ExternalResources = []v1alpha1.PipelineExternalResource{}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if p, _ = srv.Pipeline(cr.Status.AtProvider.ID); len(p.Spec.ExternalResources) != 0 {
+		t.Errorf("e.Update(...): want no external resources, got %+v", p.Spec.ExternalResources)
+	}
+
+	// CodeFresh masks encrypted trigger variables, so a change of their value
+	// is found by hash and synced by Update.
+	cr.Spec.ForProvider.Spec.Triggers = []v1alpha1.PipelineTrigger{{Name: "push", Variables: []v1alpha1.PipelineVariable{{Key: "TOKEN", Value: "secret-1", Encrypted: true}}}}
+	for _, token := range []string{"secret-1", "secret-2"} {
+		cr.Spec.ForProvider.Spec.Triggers[0].Variables[0].Value = token
+		if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+			t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+		}
+		if _, err := e.Update(ctx, cr); err != nil {
+			t.Fatalf("e.Update(...): %v", err)
+		}
+		if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+			t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+		}
+	}
+
+	// Labels and mirrored CR labels are synced by Update once Observe finds
+	// them outdated.
+	cr.SetLabels(map[string]string{"team": "payments"})
+	cr.Spec.ForProvider.MirrorLabels = []string{"team"}
+	cr.Spec.ForProvider.Metadata.Labels = map[string][]string{"tags": {"ci"}}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"team": "payments"}, srv.Annotations(v1alpha1.AnnotationEntityPipeline, cr.Status.AtProvider.ID)); diff != "" {
+		t.Errorf("e.Update(...): -want annotations, +got annotations:\n%s", diff)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+
+	// Deprecating the pipeline declaratively surfaces the Deprecated
+	// condition once CodeFresh reports it.
+	cr.Spec.ForProvider.Metadata.Deprecate = &v1alpha1.PipelineDeprecate{RepoPipeline: pointer.Bool(true)}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+	if c := cr.GetCondition(v1alpha1.TypeDeprecated); c.Status != corev1.ConditionTrue {
+		t.Errorf("e.Observe(...): want Deprecated condition, got %+v", c)
+	}
+
+	if err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("e.Delete(...): %v", err)
+	}
+	got, err = e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if got.ResourceExists {
+		t.Error("e.Observe(...): pipeline should no longer exist")
+	}
+}
+
+func TestAdditiveManagementAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	e := external{
+		client:   &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Pipeline{
+		Spec: v1alpha1.PipelineSpec{
+			ForProvider: v1alpha1.PipelineParameters{
+				Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline", Labels: map[string][]string{"tags": {"a"}}},
+				Spec: v1alpha1.PipelineSpecStruct{
+					Triggers:  []v1alpha1.PipelineTrigger{{Name: "push"}},
+					Variables: []v1alpha1.PipelineVariable{{Key: "A", Value: "a"}},
+				},
+				ManagementMode: v1alpha1.ManagementModeAdditive,
+			},
+		},
+	}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+
+	// Tags, variables and triggers added in the CodeFresh UI are not drift.
+	patch := client.PipelinePatch{
+		Metadata: client.PipelinePatchMetadata{Name: "project/pipeline", Labels: map[string][]string{"tags": {"a", "ui"}}},
+		Spec: &v1alpha1.PipelineSpecRequest{PipelineSpecStruct: v1alpha1.PipelineSpecStruct{
+			Triggers:  []v1alpha1.PipelineTrigger{{Name: "push"}, {Name: "ui"}},
+			Variables: []v1alpha1.PipelineVariable{{Key: "A", Value: "a"}, {Key: "UI", Value: "ui"}, {Key: "UI_SECRET", Value: "secret", Encrypted: true}},
+		}},
+	}
+	if _, err := e.service.Pipelines().Patch(ctx, cr.Status.AtProvider.ID, patch); err != nil {
+		t.Fatalf("Patch(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+	want := &v1alpha1.OwnedEntries{Tags: []string{"a"}, Variables: []string{"A"}, Triggers: []string{"push"}}
+	if diff := cmp.Diff(want, cr.Status.AtProvider.Owned); diff != "" {
+		t.Errorf("e.Observe(...): -want owned, +got owned:\n%s", diff)
+	}
+
+	// Owned entries that are no longer declared are removed, others kept.
+	// The variables are up to date, so they are left out of the patch and
+	// the encrypted variable added in the UI is kept.
+	cr.Spec.ForProvider.Metadata.Labels = map[string][]string{"tags": {"b"}}
+	cr.Spec.ForProvider.Spec.Triggers = []v1alpha1.PipelineTrigger{{Name: "pr"}}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	// The entries added in the UI changed the pipeline since it was created,
+	// so the update conflicts until their revision is accepted.
+	if _, err := e.Update(ctx, cr); !client.IsConflict(err) {
+		t.Fatalf("e.Update(...): want conflict, got %v", err)
+	}
+	if _, err := e.Observe(ctx, cr); err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyAcceptRevision: strconv.Itoa(cr.Status.AtProvider.Revision)})
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	p, _ := srv.Pipeline(cr.Status.AtProvider.ID)
+	if diff := cmp.Diff([]string{"b", "ui"}, p.Metadata.Labels["tags"]); diff != "" {
+		t.Errorf("e.Update(...): -want tags, +got tags:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"pr", "ui"}, triggerNames(p.Spec.Triggers)); diff != "" {
+		t.Errorf("e.Update(...): -want triggers, +got triggers:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"A", "UI", "UI_SECRET"}, variableKeys(p.Spec.Variables)); diff != "" {
+		t.Errorf("e.Update(...): -want variables, +got variables:\n%s", diff)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+
+	// Variables cannot be updated without removing the encrypted variable
+	// added in the UI, so the update is refused.
+	cr.Spec.ForProvider.Spec.Variables = []v1alpha1.PipelineVariable{{Key: "B", Value: "b"}}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	r := &recorder{}
+	e.recorder = r
+	wantErr := errors.Errorf(errFmtForeignSecrets, "UI_SECRET")
+	if _, err := e.Update(ctx, cr); !cmp.Equal(wantErr, err, test.EquateErrors()) {
+		t.Fatalf("e.Update(...): want error %v, got %v", wantErr, err)
+	}
+	if diff := cmp.Diff([]event.Event{event.Warning(reasonVariablesUpdateRefused, wantErr)}, r.events, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Update(...): -want events, +got events:\n%s", diff)
+	}
+	p, _ = srv.Pipeline(cr.Status.AtProvider.ID)
+	if diff := cmp.Diff([]string{"A", "UI", "UI_SECRET"}, variableKeys(p.Spec.Variables)); diff != "" {
+		t.Errorf("e.Update(...): -want variables, +got variables:\n%s", diff)
+	}
+}
+
+func TestCreateKeepsStepOrder(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	e := external{
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	want := []string{"zeta", "alpha", "mid"}
+	cr := &v1alpha1.Pipeline{
+		Spec: v1alpha1.PipelineSpec{
+			ForProvider: v1alpha1.PipelineParameters{
+				Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+				Spec: v1alpha1.PipelineSpecStruct{
+					Steps: map[string]v1alpha1.PipelineStep{want[0]: {}, want[1]: {}, want[2]: {}},
+				},
+			},
+		},
+	}
+	cr.SetStepOrder(want)
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	p, _ := srv.Pipeline(cr.Status.AtProvider.ID)
+	if diff := cmp.Diff(want, stepNames(responseSteps(p.Spec.Steps))); diff != "" {
+		t.Errorf("e.Create(...): steps should be created in declaration order: -want, +got:\n%s", diff)
+	}
+	got, err := e.Observe(context.Background(), cr)
+	if err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+}
+
+func TestConflictAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	e := external{
+		client:   &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Pipeline{
+		Spec: v1alpha1.PipelineSpec{
+			ForProvider: v1alpha1.PipelineParameters{
+				Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+				Spec:     v1alpha1.PipelineSpecStruct{Priority: pointer.Int(1), Concurrency: pointer.Int(2)},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	id := cr.Status.AtProvider.ID
+
+	cr.Spec.ForProvider.Spec.Priority = pointer.Int(5)
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+
+	// Someone changes the pipeline in the UI between Observe and Update.
+	srv.EditPipeline(id, func(p *v1alpha1.PipelineDocument) { p.Spec.Concurrency = pointer.Int(3) })
+
+	if _, err := e.Update(ctx, cr); !client.IsConflict(err) {
+		t.Fatalf("e.Update(...): want conflict, got %v", err)
+	}
+	if got := cr.GetCondition(v1alpha1.TypeConflictDetected); got.Status != corev1.ConditionTrue || got.Reason != v1alpha1.ReasonRevisionConflict {
+		t.Errorf("e.Update(...): want ConflictDetected condition, got %+v", got)
+	}
+	if p, _ := srv.Pipeline(id); *p.Spec.Concurrency != 3 || *p.Spec.Priority != 1 {
+		t.Errorf("e.Update(...): want the change made in the UI kept, got concurrency %d and priority %d", *p.Spec.Concurrency, *p.Spec.Priority)
+	}
+
+	// Later reconciles keep conflicting rather than overwrite the change.
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); !client.IsConflict(err) {
+		t.Fatalf("e.Update(...): want conflict, got %v", err)
+	}
+	if p, _ := srv.Pipeline(id); *p.Spec.Concurrency != 3 {
+		t.Errorf("e.Update(...): want the change made in the UI kept, got concurrency %d", *p.Spec.Concurrency)
+	}
+
+	// Accepting the revision of the change updates the pipeline against it.
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyAcceptRevision: strconv.Itoa(cr.Status.AtProvider.Revision)})
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if got := cr.GetCondition(v1alpha1.TypeConflictDetected); got.Status != corev1.ConditionFalse {
+		t.Errorf("e.Update(...): want ConflictDetected condition cleared, got %+v", got)
+	}
+	p, _ := srv.Pipeline(id)
+	if *p.Spec.Concurrency != 2 || *p.Spec.Priority != 5 {
+		t.Errorf("e.Update(...): want concurrency 2 and priority 5, got %d and %d", *p.Spec.Concurrency, *p.Spec.Priority)
+	}
+	if cr.Status.AtProvider.Revision != p.Metadata.Revision || cr.Status.AtProvider.AppliedRevision != p.Metadata.Revision {
+		t.Errorf("e.Update(...): want revision %d recorded, got observed %d and applied %d", p.Metadata.Revision, cr.Status.AtProvider.Revision, cr.Status.AtProvider.AppliedRevision)
+	}
+}
+
+func TestConflictBetweenReconcilesAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project"})
+
+	e := external{
+		client:   &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Pipeline{
+		Spec: v1alpha1.PipelineSpec{
+			ForProvider: v1alpha1.PipelineParameters{
+				Metadata: v1alpha1.PipelineMetadata{Name: "project/pipeline"},
+				Spec:     v1alpha1.PipelineSpecStruct{Priority: pointer.Int(1), Concurrency: pointer.Int(2)},
+			},
+		},
+	}
+	cr.SetGeneration(1)
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	id := cr.Status.AtProvider.ID
+	applied := cr.Status.AtProvider.AppliedRevision
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want up to date pipeline, got %+v (%v)", got, err)
+	}
+
+	// Someone changes the pipeline in the UI after one reconcile, and the
+	// spec changes before the next.
+	srv.EditPipeline(id, func(p *v1alpha1.PipelineDocument) { p.Spec.Concurrency = pointer.Int(3) })
+	cr.Spec.ForProvider.Spec.Priority = pointer.Int(5)
+	cr.SetGeneration(2)
+
+	// Observe records the new revision, but the update is still made against
+	// the revision the provider applied.
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if cr.Status.AtProvider.Revision == applied || cr.Status.AtProvider.AppliedRevision != applied {
+		t.Fatalf("e.Observe(...): want observed revision changed and applied revision %d kept, got observed %d and applied %d", applied, cr.Status.AtProvider.Revision, cr.Status.AtProvider.AppliedRevision)
+	}
+	if _, err := e.Update(ctx, cr); !client.IsConflict(err) {
+		t.Fatalf("e.Update(...): want conflict, got %v", err)
+	}
+	if got := cr.GetCondition(v1alpha1.TypeConflictDetected); got.Status != corev1.ConditionTrue || got.Reason != v1alpha1.ReasonRevisionConflict {
+		t.Errorf("e.Update(...): want ConflictDetected condition, got %+v", got)
+	}
+	if p, _ := srv.Pipeline(id); *p.Spec.Concurrency != 3 || *p.Spec.Priority != 1 {
+		t.Errorf("e.Update(...): want the change made in the UI kept, got concurrency %d and priority %d", *p.Spec.Concurrency, *p.Spec.Priority)
+	}
+
+	// The next reconciles keep conflicting rather than overwrite the change.
+	for i := 0; i < 2; i++ {
+		if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+			t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+		}
+		if _, err := e.Update(ctx, cr); !client.IsConflict(err) {
+			t.Fatalf("e.Update(...): want conflict, got %v", err)
+		}
+		if p, _ := srv.Pipeline(id); *p.Spec.Concurrency != 3 || *p.Spec.Priority != 1 {
+			t.Errorf("e.Update(...): want the change made in the UI kept, got concurrency %d and priority %d", *p.Spec.Concurrency, *p.Spec.Priority)
+		}
+	}
+
+	// Once the spec changes after the conflict, the pipeline is updated
+	// against the revision of the change.
+	cr.Spec.ForProvider.Spec.Priority = pointer.Int(6)
+	cr.SetGeneration(3)
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated pipeline, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	p, _ := srv.Pipeline(id)
+	if *p.Spec.Concurrency != 2 || *p.Spec.Priority != 6 {
+		t.Errorf("e.Update(...): want concurrency 2 and priority 6, got %d and %d", *p.Spec.Concurrency, *p.Spec.Priority)
+	}
+	if got := cr.GetCondition(v1alpha1.TypeConflictDetected); got.Status != corev1.ConditionFalse || cr.Status.AtProvider.ConflictGeneration != 0 {
+		t.Errorf("e.Update(...): want conflict cleared, got %+v and generation %d", got, cr.Status.AtProvider.ConflictGeneration)
+	}
+	if cr.Status.AtProvider.AppliedRevision != p.Metadata.Revision {
+		t.Errorf("e.Update(...): want applied revision %d recorded, got %d", p.Metadata.Revision, cr.Status.AtProvider.AppliedRevision)
+	}
+}
+
+// stepNames returns the names of the supplied steps, in order.
+func stepNames(steps []v1alpha1.PipelineStep) []string {
+	names := make([]string, len(steps))
+	for i, st := range steps {
+		names[i] = st.Name
+	}
+	return names
+}
@@ -0,0 +1,384 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinerun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+	apisv1alpha1 "crossplane-provider-codefresh/apis/v1alpha1"
+	"crossplane-provider-codefresh/internal/dryrun"
+	"crossplane-provider-codefresh/internal/features"
+
+	codefreshclient "crossplane-provider-codefresh/internal/client"
+	"crossplane-provider-codefresh/internal/constants"
+)
+
+const (
+	errNotPipelineRun  = "managed resource is not a PipelineRun custom resource"
+	errNoPipeline      = "no pipeline to run: set pipelineId, pipelineIdRef or pipelineIdSelector"
+	errRunningPipeline = "error running pipeline in CodeFresh"
+	errGettingBuild    = "error getting build from CodeFresh"
+	errGettingProgress = "error getting build steps from CodeFresh"
+
+	reasonBuildSucceeded event.Reason = "BuildSucceeded"
+	reasonBuildFailed    event.Reason = "BuildFailed"
+
+	// The log excerpt of a failed step is bounded to its last lines, and to
+	// the last bytes of those, to keep it well within the size of an event
+	// and of the object.
+	maxLogLines = 20
+	maxLogBytes = 2048
+)
+
+// Setup adds a controller that reconciles PipelineRun managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.PipelineRunGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(dryrun.NewConnecter(&connector{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			newServiceFn: func(creds []byte, endpoint string, logger logging.Logger) (codefreshclient.CodeFreshAPI, error) {
+				return codefreshclient.NewCodeFreshService(creds, endpoint, o.Logger)
+			},
+			logger:   o.Logger.WithValues("controller", name),
+			recorder: recorder,
+		}, v1alpha1.PipelineRunKind, o.Features.Enabled(features.DryRun), recorder)),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.PipelineRunGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1alpha1.PipelineRun{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(creds []byte, endpoint string, logger logging.Logger) (codefreshclient.CodeFreshAPI, error)
+	logger       logging.Logger
+	recorder     event.Recorder
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.PipelineRun)
+	if !ok {
+		return nil, errors.New(errNotPipelineRun)
+	}
+
+	c.logger.Info("Connecting to CodeFresh", "pipelineRun", cr.GetName())
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, constants.ErrTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, constants.ErrGetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, constants.ErrGetCreds)
+	}
+
+	service, err := c.newServiceFn(data, pointer.StringDeref(pc.Spec.Endpoint, ""), c.logger)
+	if err != nil {
+		return nil, errors.Wrap(err, constants.ErrNewClient)
+	}
+
+	return &external{service: service, logger: c.logger, recorder: c.recorder}, nil
+}
+
+// An external runs a pipeline once and tracks the build it started. Builds
+// are never updated, stopped or deleted; they remain in the CodeFresh build
+// history.
+type external struct {
+	service  codefreshclient.CodeFreshAPI
+	logger   logging.Logger
+	recorder event.Recorder
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.PipelineRun)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotPipelineRun)
+	}
+
+	// There is nothing to delete, so a deleted run is reported as gone.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	buildID := externalID(cr)
+	if buildID == "" {
+		// No build ID means the pipeline hasn't been run yet.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// A finished build won't change, so there's no need to poll it again.
+	if cr.Status.AtProvider.BuildID == buildID && cr.Status.AtProvider.Status.Terminal() {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	build, err := c.service.Builds().Get(ctx, buildID)
+	if err != nil {
+		// A build that can't be found is not reported as gone, as that would
+		// run the pipeline again.
+		return managed.ExternalObservation{}, errors.Wrap(err, errGettingBuild)
+	}
+
+	obs := observe(build)
+	if build.Progress != "" {
+		progress, err := c.service.Builds().Progress(ctx, build.Progress)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGettingProgress)
+		}
+		observeSteps(&obs, progress)
+	}
+
+	finished := build.Status.Terminal() && !cr.Status.AtProvider.Status.Terminal()
+	setBuild(&cr.Status.AtProvider, obs)
+
+	// Only a successful build makes the run ready.
+	switch {
+	case build.Status == v1alpha1.BuildStatusSuccess:
+		cr.SetConditions(xpv1.Available())
+	case build.Status.Terminal():
+		cr.SetConditions(xpv1.Unavailable().WithMessage(failure(obs)))
+	default:
+		cr.SetConditions(xpv1.Creating())
+	}
+
+	switch {
+	case finished && build.Status == v1alpha1.BuildStatusSuccess:
+		c.recorder.Event(cr, event.Normal(reasonBuildSucceeded, fmt.Sprintf("Build %s succeeded after %s", build.ID, duration(obs.Duration))))
+	case finished:
+		msg := failure(obs)
+		if obs.LogExcerpt != "" {
+			msg += ":\n" + obs.LogExcerpt
+		}
+		c.recorder.Event(cr, event.Warning(reasonBuildFailed, errors.New(msg)))
+	}
+
+	c.logger.Debug("Observed build", "buildID", build.ID, "status", build.Status, "failedStep", obs.FailedStep)
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+	}, nil
+}
+
+// externalID returns the CodeFresh ID of the build the run started. The ID
+// recorded in status takes precedence; otherwise an external name that was set
+// by Create, or explicitly to track an existing build, is used.
+func externalID(cr *v1alpha1.PipelineRun) string {
+	if cr.Status.AtProvider.BuildID != "" {
+		return cr.Status.AtProvider.BuildID
+	}
+	if en := meta.GetExternalName(cr); en != cr.GetName() {
+		return en
+	}
+	return ""
+}
+
+// observe returns the observable fields of the supplied build.
+func observe(b *v1alpha1.Build) v1alpha1.PipelineRunObservation {
+	return v1alpha1.PipelineRunObservation{
+		BuildID:    b.ID,
+		Status:     b.Status,
+		Progress:   b.Progress,
+		StartedAt:  parseTime(b.Started),
+		FinishedAt: parseTime(b.Finished),
+		Duration:   between(b.Started, b.Finished),
+	}
+}
+
+// setBuild records the fields observed from a build in at, leaving the others,
+// such as the planned action, in place.
+func setBuild(at *v1alpha1.PipelineRunObservation, obs v1alpha1.PipelineRunObservation) {
+	at.BuildID = obs.BuildID
+	at.Status = obs.Status
+	at.Progress = obs.Progress
+	at.StartedAt = obs.StartedAt
+	at.FinishedAt = obs.FinishedAt
+	at.Duration = obs.Duration
+	at.Steps = obs.Steps
+	at.FailedStep = obs.FailedStep
+	at.LogExcerpt = obs.LogExcerpt
+}
+
+// observeSteps records the steps of the supplied progress in obs, along with
+// the step the build failed at and the tail of its log.
+func observeSteps(obs *v1alpha1.PipelineRunObservation, p *v1alpha1.BuildProgress) {
+	for _, s := range p.Steps {
+		obs.Steps = append(obs.Steps, v1alpha1.PipelineRunStep{
+			Name:     s.Name,
+			Status:   s.Status,
+			Duration: between(s.Started, s.Finished),
+		})
+		if obs.FailedStep == "" && s.Status.Terminal() && s.Status != v1alpha1.BuildStatusSuccess {
+			obs.FailedStep = s.Name
+			obs.LogExcerpt = tail(s.Logs, maxLogLines, maxLogBytes)
+		}
+	}
+}
+
+// failure describes how the build of a finished, unsuccessful run failed.
+func failure(obs v1alpha1.PipelineRunObservation) string {
+	msg := fmt.Sprintf("Build %s finished with status %s", obs.BuildID, obs.Status)
+	if obs.FailedStep != "" {
+		msg += fmt.Sprintf(" at step %s", obs.FailedStep)
+	}
+	if obs.Duration != nil {
+		msg += fmt.Sprintf(" after %s", obs.Duration.Duration)
+	}
+	return msg
+}
+
+// tail returns the last n lines of the supplied log chunks, bounded to their
+// last max bytes.
+func tail(logs []string, n, max int) string {
+	out := strings.TrimRight(strings.Join(logs, ""), "\n")
+	if lines := strings.Split(out, "\n"); len(lines) > n {
+		out = strings.Join(lines[len(lines)-n:], "\n")
+	}
+	if len(out) <= max {
+		return out
+	}
+	out = out[len(out)-max:]
+	// Don't start in the middle of a character.
+	for len(out) > 0 && !utf8.RuneStart(out[0]) {
+		out = out[1:]
+	}
+	return out
+}
+
+// between returns the duration between two RFC 3339 timestamps, or nil if
+// either is empty or malformed.
+func between(start, end string) *metav1.Duration {
+	s, e := parseTime(start), parseTime(end)
+	if s == nil || e == nil {
+		return nil
+	}
+	return &metav1.Duration{Duration: e.Sub(s.Time)}
+}
+
+// duration formats d, which may be nil.
+func duration(d *metav1.Duration) string {
+	if d == nil {
+		return "an unknown time"
+	}
+	return d.Duration.String()
+}
+
+// parseTime returns the time of an RFC 3339 timestamp, or nil if it's empty or
+// malformed.
+func parseTime(s string) *metav1.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	mt := metav1.NewTime(t)
+	return &mt
+}
+
+// Create runs the pipeline. The build ID is returned through the external
+// name, which the managed reconciler persists together with its
+// external-create annotations, so a run that may have started is never
+// repeated.
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.PipelineRun)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotPipelineRun)
+	}
+
+	pipelineID := pointer.StringDeref(cr.Spec.ForProvider.PipelineID, "")
+	if pipelineID == "" {
+		return managed.ExternalCreation{}, errors.New(errNoPipeline)
+	}
+
+	in := v1alpha1.PipelineRunRequest{
+		Branch:    pointer.StringDeref(cr.Spec.ForProvider.Branch, ""),
+		Trigger:   pointer.StringDeref(cr.Spec.ForProvider.Trigger, ""),
+		Variables: cr.Spec.ForProvider.Variables,
+		Options:   cr.Spec.ForProvider.Options,
+	}
+	buildID, err := c.service.Builds().Run(ctx, pipelineID, in)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errRunningPipeline)
+	}
+
+	cr.Status.AtProvider.BuildID = buildID
+	meta.SetExternalName(cr, buildID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+// Update does nothing. A pipeline is only run once; changes to a PipelineRun
+// don't run it again.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete does nothing. Builds remain in the CodeFresh build history, and a
+// build that is still running is not stopped.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	return nil
+}
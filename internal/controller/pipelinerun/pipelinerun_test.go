@@ -0,0 +1,446 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelinerun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+	"crossplane-provider-codefresh/internal/client"
+	"crossplane-provider-codefresh/internal/client/fake"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+// recorder records the events it is sent.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
+func pipelineRun(o ...func(*v1alpha1.PipelineRun)) *v1alpha1.PipelineRun {
+	cr := &v1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run"},
+		Spec: v1alpha1.PipelineRunSpec{
+			ForProvider: v1alpha1.PipelineRunParameters{PipelineID: pointer.String("pipeline")},
+		},
+	}
+	meta.SetExternalName(cr, cr.GetName())
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+func withBuild(id string, s v1alpha1.BuildStatus) func(*v1alpha1.PipelineRun) {
+	return func(cr *v1alpha1.PipelineRun) {
+		meta.SetExternalName(cr, id)
+		cr.Status.AtProvider.BuildID = id
+		cr.Status.AtProvider.Status = s
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o          managed.ExternalObservation
+		status     v1alpha1.PipelineRunObservation
+		conditions []xpv1.Condition
+		events     []event.Event
+		err        error
+	}
+
+	errBoom := errors.New("boom")
+	started := metav1.NewTime(time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC))
+	finished := metav1.NewTime(time.Date(2023, 6, 1, 10, 5, 0, 0, time.UTC))
+	fiveMinutes := &metav1.Duration{Duration: 5 * time.Minute}
+	oneMinute := &metav1.Duration{Duration: time.Minute}
+	steps := &v1alpha1.BuildProgress{Steps: []v1alpha1.BuildStep{
+		{Name: "clone", Status: v1alpha1.BuildStatusSuccess, Started: "2023-06-01T10:00:00Z", Finished: "2023-06-01T10:01:00Z", Logs: []string{"cloned\n"}},
+		{Name: "test", Status: v1alpha1.BuildStatusError, Started: "2023-06-01T10:01:00Z", Finished: "2023-06-01T10:05:00Z", Logs: []string{"ok 1\n", "not ok 2\nFAIL\n"}},
+		{Name: "deploy", Status: v1alpha1.BuildStatusPending},
+	}}
+	failedObs := v1alpha1.PipelineRunObservation{
+		BuildID:    "build",
+		Status:     v1alpha1.BuildStatusError,
+		Progress:   "progress",
+		StartedAt:  &started,
+		FinishedAt: &finished,
+		Duration:   fiveMinutes,
+		Steps: []v1alpha1.PipelineRunStep{
+			{Name: "clone", Status: v1alpha1.BuildStatusSuccess, Duration: oneMinute},
+			{Name: "test", Status: v1alpha1.BuildStatusError, Duration: &metav1.Duration{Duration: 4 * time.Minute}},
+			{Name: "deploy", Status: v1alpha1.BuildStatusPending},
+		},
+		FailedStep: "test",
+		LogExcerpt: "ok 1\nnot ok 2\nFAIL",
+	}
+	failedMsg := "Build build finished with status error at step test after 5m0s"
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		setup  func(*client.MockCodeFreshAPIClient)
+		want   want
+	}{
+		"NotRun": {
+			reason: "A PipelineRun without a build should not exist yet.",
+			mg:     pipelineRun(),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"Running": {
+			reason: "A running build should be tracked and the PipelineRun should be creating.",
+			mg:     pipelineRun(withBuild("build", v1alpha1.BuildStatusPending)),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockGetResponse = &v1alpha1.Build{ID: "build", Status: v1alpha1.BuildStatusRunning, Progress: "progress", Started: "2023-06-01T10:00:00Z"}
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status:     v1alpha1.PipelineRunObservation{BuildID: "build", Status: v1alpha1.BuildStatusRunning, Progress: "progress", StartedAt: &started},
+				conditions: []xpv1.Condition{xpv1.Creating()},
+			},
+		},
+		"PlannedActionKept": {
+			reason: "Observing a build should not clear fields of the status that are not observed from it.",
+			mg: pipelineRun(withBuild("build", v1alpha1.BuildStatusPending), func(cr *v1alpha1.PipelineRun) {
+				cr.Status.AtProvider.PlannedAction = v1alpha1.PlannedActionNone
+			}),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockGetResponse = &v1alpha1.Build{ID: "build", Status: v1alpha1.BuildStatusRunning, Started: "2023-06-01T10:00:00Z"}
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status:     v1alpha1.PipelineRunObservation{BuildID: "build", Status: v1alpha1.BuildStatusRunning, StartedAt: &started, PlannedAction: v1alpha1.PlannedActionNone},
+				conditions: []xpv1.Condition{xpv1.Creating()},
+			},
+		},
+		"Finished": {
+			reason: "A build that reached a terminal status should make the PipelineRun available.",
+			mg:     pipelineRun(withBuild("build", v1alpha1.BuildStatusRunning)),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockGetResponse = &v1alpha1.Build{ID: "build", Status: v1alpha1.BuildStatusSuccess, Started: "2023-06-01T10:00:00Z", Finished: "2023-06-01T10:05:00Z"}
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status:     v1alpha1.PipelineRunObservation{BuildID: "build", Status: v1alpha1.BuildStatusSuccess, StartedAt: &started, FinishedAt: &finished, Duration: fiveMinutes},
+				conditions: []xpv1.Condition{xpv1.Available()},
+				events:     []event.Event{event.Normal(reasonBuildSucceeded, "Build build succeeded after 5m0s")},
+			},
+		},
+		"Failed": {
+			reason: "A failed build should report the failed step and its log, and should not make the PipelineRun ready.",
+			mg:     pipelineRun(withBuild("build", v1alpha1.BuildStatusRunning)),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockGetResponse = &v1alpha1.Build{ID: "build", Status: v1alpha1.BuildStatusError, Progress: "progress", Started: "2023-06-01T10:00:00Z", Finished: "2023-06-01T10:05:00Z"}
+				m.MockBuilds.MockProgressResponse = steps
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status:     failedObs,
+				conditions: []xpv1.Condition{xpv1.Unavailable().WithMessage(failedMsg)},
+				events:     []event.Event{event.Warning(reasonBuildFailed, errors.New(failedMsg+":\nok 1\nnot ok 2\nFAIL"))},
+			},
+		},
+		"ProgressFailed": {
+			reason: "An error getting the steps of the build should be returned.",
+			mg:     pipelineRun(withBuild("build", v1alpha1.BuildStatusRunning)),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockGetResponse = &v1alpha1.Build{ID: "build", Status: v1alpha1.BuildStatusRunning, Progress: "progress"}
+				m.MockBuilds.MockProgressErr = errBoom
+			},
+			want: want{
+				status: v1alpha1.PipelineRunObservation{BuildID: "build", Status: v1alpha1.BuildStatusRunning},
+				err:    errors.Wrap(errBoom, errGettingProgress),
+			},
+		},
+		"AlreadyFinished": {
+			reason: "A build already observed in a terminal status should not be polled again.",
+			mg:     pipelineRun(withBuild("build", v1alpha1.BuildStatusError)),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockGetErr = errBoom
+			},
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.PipelineRunObservation{BuildID: "build", Status: v1alpha1.BuildStatusError},
+			},
+		},
+		"BuildNotFound": {
+			reason: "A build that can't be found should be an error rather than run the pipeline again.",
+			mg:     pipelineRun(withBuild("build", v1alpha1.BuildStatusRunning)),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockGetErr = client.ErrResourceNotFound
+			},
+			want: want{
+				status: v1alpha1.PipelineRunObservation{BuildID: "build", Status: v1alpha1.BuildStatusRunning},
+				err:    errors.Wrap(client.ErrResourceNotFound, errGettingBuild),
+			},
+		},
+		"Deleted": {
+			reason: "A deleted PipelineRun should be reported as gone, as builds can't be deleted.",
+			mg: pipelineRun(withBuild("build", v1alpha1.BuildStatusRunning), func(cr *v1alpha1.PipelineRun) {
+				cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			}),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: false},
+				status: v1alpha1.PipelineRunObservation{BuildID: "build", Status: v1alpha1.BuildStatusRunning},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &client.MockCodeFreshAPIClient{}
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+			r := &recorder{}
+			e := external{service: m, logger: logging.NewNopLogger(), recorder: r}
+			got, err := e.Observe(context.TODO(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			cr := tc.mg.(*v1alpha1.PipelineRun)
+			if diff := cmp.Diff(tc.want.status, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			for _, c := range tc.want.conditions {
+				if diff := cmp.Diff(c, cr.GetCondition(c.Type), test.EquateConditions()); diff != "" {
+					t.Errorf("\n%s\ne.Observe(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.events, r.events, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTail(t *testing.T) {
+	many := make([]string, 50)
+	for i := range many {
+		many[i] = fmt.Sprintf("line %d\n", i)
+	}
+
+	cases := map[string]struct {
+		reason string
+		logs   []string
+		n      int
+		max    int
+		want   string
+	}{
+		"Empty": {
+			reason: "No logs should give an empty excerpt.",
+			n:      20,
+			max:    2048,
+			want:   "",
+		},
+		"Chunks": {
+			reason: "Log chunks should be joined and split into lines.",
+			logs:   []string{"a\nb", "c\n", "d\n"},
+			n:      20,
+			max:    2048,
+			want:   "a\nbc\nd",
+		},
+		"Lines": {
+			reason: "Only the last lines should be kept.",
+			logs:   many,
+			n:      2,
+			max:    2048,
+			want:   "line 48\nline 49",
+		},
+		"Bytes": {
+			reason: "The excerpt should be bounded in bytes without splitting characters.",
+			logs:   []string{"ab€cd"},
+			n:      20,
+			max:    4,
+			want:   "cd",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tail(tc.logs, tc.n, tc.max)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntail(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if len(got) > tc.max || strings.Count(got, "\n") >= tc.n {
+				t.Errorf("\n%s\ntail(...): excerpt %q exceeds its bounds", tc.reason, got)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		id  string
+		err error
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		mg     *v1alpha1.PipelineRun
+		setup  func(*client.MockCodeFreshAPIClient)
+		want   want
+	}{
+		"Run": {
+			reason: "Running the pipeline should record the build ID as the external name.",
+			mg:     pipelineRun(),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockRunResponse = "build"
+			},
+			want: want{id: "build"},
+		},
+		"NoPipeline": {
+			reason: "A PipelineRun whose pipeline isn't resolved should not run anything.",
+			mg: pipelineRun(func(cr *v1alpha1.PipelineRun) {
+				cr.Spec.ForProvider.PipelineID = nil
+			}),
+			want: want{id: "run", err: errors.New(errNoPipeline)},
+		},
+		"RunFailed": {
+			reason: "An error running the pipeline should be returned.",
+			mg:     pipelineRun(),
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockBuilds.MockRunErr = errBoom
+			},
+			want: want{id: "run", err: errors.Wrap(errBoom, errRunningPipeline)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &client.MockCodeFreshAPIClient{}
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+			e := external{service: m, logger: logging.NewNopLogger()}
+			_, err := e.Create(context.TODO(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLifecycleAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	pipelineID := srv.AddPipeline(v1alpha1.PipelineDocument{
+		Metadata: v1alpha1.PipelineMetadataResponse{Name: "project/pipeline"},
+		Spec:     v1alpha1.PipelineSpecResponse{Triggers: []v1alpha1.PipelineTrigger{{Name: "push"}}},
+	})
+
+	r := &recorder{}
+	e := external{
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: r,
+	}
+	cr := pipelineRun(func(cr *v1alpha1.PipelineRun) {
+		cr.Spec.ForProvider = v1alpha1.PipelineRunParameters{
+			PipelineID: pointer.String(pipelineID),
+			Branch:     pointer.String("main"),
+			Trigger:    pointer.String("push"),
+			Variables:  map[string]string{"SMOKE": "true"},
+			Options:    &v1alpha1.PipelineRunOptions{NoCache: true, ResetVolume: true},
+		}
+	})
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	reqs := srv.Requests()
+	var got v1alpha1.PipelineRunRequest
+	if err := json.Unmarshal([]byte(reqs[len(reqs)-1].Body), &got); err != nil {
+		t.Fatalf("json.Unmarshal(...): %v", err)
+	}
+	want := v1alpha1.PipelineRunRequest{
+		Branch:    "main",
+		Trigger:   "push",
+		Variables: map[string]string{"SMOKE": "true"},
+		Options:   &v1alpha1.PipelineRunOptions{NoCache: true, ResetVolume: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("e.Create(...): -want run request, +got run request:\n%s", diff)
+	}
+
+	id := meta.GetExternalName(cr)
+	for _, s := range []v1alpha1.BuildStatus{v1alpha1.BuildStatusPending, v1alpha1.BuildStatusRunning, v1alpha1.BuildStatusError} {
+		srv.SetBuildStatus(id, s)
+		if s == v1alpha1.BuildStatusRunning {
+			srv.AddBuildStep(id, v1alpha1.BuildStep{Name: "test", Status: v1alpha1.BuildStatusError, Logs: []string{"FAIL\n"}})
+		}
+		if _, err := e.Observe(ctx, cr); err != nil {
+			t.Fatalf("e.Observe(...): %v", err)
+		}
+		if cr.Status.AtProvider.Status != s {
+			t.Errorf("e.Observe(...): want status %q, got %q", s, cr.Status.AtProvider.Status)
+		}
+	}
+	if cr.Status.AtProvider.StartedAt == nil || cr.Status.AtProvider.FinishedAt == nil {
+		t.Errorf("e.Observe(...): want start and finish times, got %+v", cr.Status.AtProvider)
+	}
+	if cr.Status.AtProvider.FailedStep != "test" || cr.Status.AtProvider.LogExcerpt != "FAIL" {
+		t.Errorf("e.Observe(...): want failed step test with its log, got %+v", cr.Status.AtProvider)
+	}
+	if c := cr.GetCondition(xpv1.TypeReady); c.Reason != xpv1.ReasonUnavailable {
+		t.Errorf("e.Observe(...): want unavailable, got %+v", c)
+	}
+	if len(r.events) != 1 || r.events[0].Reason != reasonBuildFailed {
+		t.Errorf("e.Observe(...): want one %s event, got %+v", reasonBuildFailed, r.events)
+	}
+}
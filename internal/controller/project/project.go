@@ -18,17 +18,24 @@ package project
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -38,40 +45,63 @@ import (
 
 	codefreshclient "crossplane-provider-codefresh/internal/client"
 	"crossplane-provider-codefresh/internal/constants"
+	"crossplane-provider-codefresh/internal/drift"
+	"crossplane-provider-codefresh/internal/dryrun"
 	"crossplane-provider-codefresh/internal/helpers"
+	"crossplane-provider-codefresh/internal/normalize"
 )
 
 const (
-	errNotProject            = "managed resource is not a Project custom resource"
-	errCreatingProject       = "error creating project in CodeFresh"
-	errUpdatingProject       = "error updating project in CodeFresh"
-	errUpdatingProjectStatus = "error updating project status with project ID"
-	errDeletingProject       = "error deleting project in CodeFresh"
+	errNotProject         = "managed resource is not a Project custom resource"
+	errCreatingProject    = "error creating project in CodeFresh"
+	errUpdatingProject    = "error updating project in CodeFresh"
+	errLookingUpProject   = "error looking up existing project by name in CodeFresh"
+	errDeletingProject    = "error deleting project in CodeFresh"
+	errGettingProject     = "error getting project from CodeFresh"
+	errResolvingVariables = "error resolving project variables"
+	errFmtProjectNotEmpty = "refusing to delete project: it still contains %d pipeline(s)"
+	errFmtForeignSecrets  = "refusing to update project variables: CodeFresh replaces all of them but masks encrypted ones, so sending them would remove the encrypted variable(s) %s added elsewhere"
+
+	reasonOrphanedNonEmptyProject event.Reason = "OrphanedNonEmptyProject"
+	msgFmtOrphanedNonEmptyProject              = "Leaving project in CodeFresh: it still contains %d pipeline(s)"
+
+	reasonDeleteRefused event.Reason = "DeleteRefused"
+
+	reasonVariablesUpdateRefused event.Reason = "VariablesUpdateRefused"
+
+	reasonDriftDetected event.Reason = "DriftDetected"
 )
 
 // Setup adds a controller that reconciles Project managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.ProjectGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
 	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.ProjectGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(dryrun.NewConnecter(&connector{
 			kube:  mgr.GetClient(),
 			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: func(creds []byte, logger logging.Logger) (interface{}, error) {
-				return codefreshclient.NewCodeFreshService(creds, o.Logger)
+			newServiceFn: func(creds []byte, endpoint string, logger logging.Logger) (codefreshclient.CodeFreshAPI, error) {
+				return codefreshclient.NewCodeFreshService(creds, endpoint, o.Logger)
 			},
-			logger: o.Logger.WithValues("controller", name),
-		}),
+			logger:   o.Logger.WithValues("controller", name),
+			recorder: recorder,
+		}, v1alpha1.ProjectKind, o.Features.Enabled(features.DryRun), recorder)),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.ProjectGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -86,8 +116,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds []byte, logger logging.Logger) (interface{}, error)
+	newServiceFn func(creds []byte, endpoint string, logger logging.Logger) (codefreshclient.CodeFreshAPI, error)
 	logger       logging.Logger
+	recorder     event.Recorder
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -113,18 +144,12 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, constants.ErrGetCreds)
 	}
 
-	svc, err := c.newServiceFn(data, c.logger)
+	service, err := c.newServiceFn(data, pointer.StringDeref(pc.Spec.Endpoint, ""), c.logger)
 	if err != nil {
 		return nil, errors.Wrap(err, constants.ErrNewClient)
 	}
 
-	// Assert the type of svc to CodeFreshAPI
-	service, ok := svc.(codefreshclient.CodeFreshAPI)
-	if !ok {
-		return nil, errors.New(constants.ErrAssertCodeFreshService)
-	}
-
-	return newExternal(c.kube, c.logger, service), nil
+	return newExternal(c.kube, c.logger, c.recorder, service), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -132,15 +157,17 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	client client.Client
 	/*service interface{}*/
-	service codefreshclient.CodeFreshAPI
-	logger  logging.Logger
+	service  codefreshclient.CodeFreshAPI
+	logger   logging.Logger
+	recorder event.Recorder
 }
 
-func newExternal(client client.Client, logger logging.Logger, service codefreshclient.CodeFreshAPI) *external {
+func newExternal(client client.Client, logger logging.Logger, recorder event.Recorder, service codefreshclient.CodeFreshAPI) *external {
 	return &external{
-		client:  client,
-		logger:  logger,
-		service: service,
+		client:   client,
+		logger:   logger,
+		recorder: recorder,
+		service:  service,
 	}
 }
 
@@ -154,57 +181,260 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(constants.ErrExpectedCodeFreshClient)
 	}
 
-	projectID := cr.Status.AtProvider.ProjectID
+	projectID := externalID(cr)
 	if projectID == "" {
 		// No project ID means the project hasn't been created yet.
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	var projectDetails v1alpha1.ProjectDetails
-	err := c.service.GetResource(ctx, "projects", projectID, &projectDetails)
+	projectDetails, err := c.service.Projects().Get(ctx, projectID)
 	if err != nil {
 		// Check if the error is due to the project not being found
-		if errors.Is(err, codefreshclient.ErrResourceNotFound) || helpers.IsResourceNotFoundErr(err, "Project") {
+		if codefreshclient.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{}, err
 	}
 
-	var variables []v1alpha1.ProjectVariable //nolint:prealloc
-	for _, v := range cr.Spec.ForProvider.ProjectVariables {
-		variables = append(variables, v1alpha1.ProjectVariable{Key: v.Key, Value: v.Value})
+	// A non-empty project that should be orphaned is reported as gone, so that
+	// its managed resource can be deleted without deleting the project.
+	if meta.WasDeleted(cr) && cr.Spec.ForProvider.DeletePolicyForNonEmpty == v1alpha1.NonEmptyDeletePolicyOrphan && projectDetails.ProjectTotalPipelinesNumber > 0 {
+		c.recorder.Event(cr, event.Normal(reasonOrphanedNonEmptyProject, fmt.Sprintf(msgFmtOrphanedNonEmptyProject, projectDetails.ProjectTotalPipelinesNumber)))
+		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	// Check if the project name, tags, and variables are up to date
-	nameUpToDate := projectDetails.ProjectName == cr.Spec.ForProvider.ProjectName
-	tagsUpToDate := helpers.AreTagsEqual(projectDetails.ProjectTags, cr.Spec.ForProvider.ProjectTags)
-	varsUpToDate := helpers.AreSlicesEqual(projectDetails.ProjectVariables, variables)
+	cr.Status.AtProvider.ProjectID = projectID
+	cr.SetConditions(xpv1.Available())
 
-	resourceUpToDate := nameUpToDate && tagsUpToDate && varsUpToDate
+	variables, err := c.resolveVariables(ctx, cr.Spec.ForProvider.ProjectVariables)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errResolvingVariables)
+	}
+
+	current := cr.Spec.ForProvider.DeepCopy()
+	lateInitialize(&cr.Spec.ForProvider, projectDetails)
+
+	// Check if the project name, image, tags, and variables are up to date
+	var diffs []drift.Difference
+	if want, got := cr.Spec.ForProvider.ProjectName, projectDetails.ProjectName; got != want {
+		diffs = append(diffs, drift.Field("projectName", want, got))
+	}
+	if want := cr.Spec.ForProvider.ProjectImage; want != nil && *want != projectDetails.ProjectImage {
+		diffs = append(diffs, drift.Field("projectImage", want, projectDetails.ProjectImage))
+	}
+	observedTags, observedVars := projectDetails.ProjectTags, projectDetails.ProjectVariables
+	if isAdditive(cr) {
+		// Only declared and owned entries are managed in Additive mode.
+		owned := ownedEntries(cr)
+		observedTags = helpers.OwnedOf(observedTags, cr.Spec.ForProvider.ProjectTags, owned.Tags, helpers.Identity)
+		observedVars = helpers.OwnedOf(observedVars, variableKeys(variables), owned.Variables, variableKey)
+	}
+	if want, got := normalize.Strings(cr.Spec.ForProvider.ProjectTags), normalize.Strings(observedTags); !cmp.Equal(got, want) {
+		diffs = append(diffs, drift.Field("projectTags", want, got))
+	}
+	diffs = append(diffs, variablesDrift(cr.Spec.ForProvider.ProjectVariables, variables, observedVars, cr.Status.AtProvider.VariableHashes, cr.GetUID())...)
+
+	resourceUpToDate := len(diffs) == 0
+	c.observeDrift(cr, diffs)
+	if resourceUpToDate {
+		// Status set by Create may have been discarded, so ownership is
+		// recorded again once CodeFresh holds the declared entries.
+		cr.Status.AtProvider.Owned = declaredEntries(cr)
+	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  resourceUpToDate,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:          true,
+		ResourceUpToDate:        resourceUpToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		ConnectionDetails:       managed.ConnectionDetails{},
 	}, nil
 }
 
+// observeDrift records a summary of the supplied differences in the status
+// and Drifted condition of the project, and emits an event when the summary
+// changes. A project that never drifted gets no condition at all.
+func (c *external) observeDrift(cr *v1alpha1.Project, diffs []drift.Difference) {
+	if len(diffs) == 0 {
+		cr.Status.AtProvider.Drift = ""
+		if cr.GetCondition(v1alpha1.TypeDrifted).Status == corev1.ConditionTrue {
+			cr.SetConditions(v1alpha1.NotDrifted())
+		}
+		return
+	}
+	summary := drift.Summary(diffs, drift.MaxSummaryLength)
+	if summary != cr.Status.AtProvider.Drift {
+		c.recorder.Event(cr, event.Normal(reasonDriftDetected, summary))
+	}
+	cr.Status.AtProvider.Drift = summary
+	cr.SetConditions(v1alpha1.Drifted().WithMessage(summary))
+}
+
+// externalID returns the CodeFresh ID of the project. The ID recorded in
+// status takes precedence; otherwise an external name that was set explicitly,
+// for example to observe an existing project, is used.
+func externalID(cr *v1alpha1.Project) string {
+	if cr.Status.AtProvider.ProjectID != "" {
+		return cr.Status.AtProvider.ProjectID
+	}
+	if en := meta.GetExternalName(cr); en != cr.GetName() {
+		return en
+	}
+	return ""
+}
+
+// isAdditive returns true if the project is managed in Additive mode.
+func isAdditive(cr *v1alpha1.Project) bool {
+	return cr.Spec.ForProvider.ManagementMode == v1alpha1.ManagementModeAdditive
+}
+
+// ownedEntries returns the entries the provider owned when it last synced the
+// project.
+func ownedEntries(cr *v1alpha1.Project) v1alpha1.OwnedEntries {
+	if cr.Status.AtProvider.Owned == nil {
+		return v1alpha1.OwnedEntries{}
+	}
+	return *cr.Status.AtProvider.Owned
+}
+
+// declaredEntries returns the entries the provider owns once CodeFresh holds
+// the declared ones: none in Authoritative mode, where it owns all entries.
+func declaredEntries(cr *v1alpha1.Project) *v1alpha1.OwnedEntries {
+	if !isAdditive(cr) {
+		return nil
+	}
+	return &v1alpha1.OwnedEntries{
+		Tags:      append([]string(nil), cr.Spec.ForProvider.ProjectTags...),
+		Variables: variableKeys(cr.Spec.ForProvider.ProjectVariables),
+	}
+}
+
+// variableKey returns the key of v.
+func variableKey(v v1alpha1.ProjectVariable) string {
+	return v.Key
+}
+
+// variableKeys returns the keys of the supplied variables.
+func variableKeys(vars []v1alpha1.ProjectVariable) []string {
+	keys := make([]string, len(vars))
+	for i, v := range vars {
+		keys[i] = v.Key
+	}
+	return keys
+}
+
+// foreignSecrets returns the keys of the encrypted variables of the observed
+// project that the provider does not manage.
+func foreignSecrets(cr *v1alpha1.Project, observed []v1alpha1.ProjectVariable) []string {
+	var keys []string
+	for _, v := range helpers.UnownedOf(observed, variableKeys(cr.Spec.ForProvider.ProjectVariables), ownedEntries(cr).Variables, variableKey) {
+		if v.Encrypted {
+			keys = append(keys, v.Key)
+		}
+	}
+	return keys
+}
+
+// resolveVariables returns a copy of the supplied variables with the values
+// of those read from a Secret or ConfigMap filled in.
+func (c *external) resolveVariables(ctx context.Context, vars []v1alpha1.ProjectVariable) ([]v1alpha1.ProjectVariable, error) {
+	var out []v1alpha1.ProjectVariable //nolint:prealloc
+	for _, v := range vars {
+		if v.ValueFrom != nil {
+			value, err := helpers.ResolveVariableValue(ctx, c.client, v.ValueFrom)
+			if err != nil {
+				return nil, errors.Wrapf(err, "variable %s", v.Key)
+			}
+			v.Value, v.ValueFrom = value, nil
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// variablesDrift returns the differences between the supplied variables and
+// those CodeFresh has, in any order. CodeFresh masks the values of encrypted
+// variables, so those are compared with the hashes of the values last sent
+// instead. The values of the declared variables read from a Secret are
+// redacted, as are those of encrypted variables.
+func variablesDrift(declared, want, got []v1alpha1.ProjectVariable, hashes map[string]string, salt types.UID) []drift.Difference {
+	secret := map[string]bool{}
+	for _, v := range declared {
+		if v.ValueFrom != nil && v.ValueFrom.SecretKeyRef != nil {
+			secret[v.Key] = true
+		}
+	}
+	same := func(w, o drift.Variable) bool {
+		switch {
+		case o.Encrypted != w.Encrypted:
+			return false
+		case w.Encrypted:
+			return hashes[w.Key] == helpers.VariableHash(salt, w.Key, w.Value)
+		}
+		return o.Value == w.Value
+	}
+	return drift.Variables("projectVariables", driftVariables(want, secret), driftVariables(got, secret), same)
+}
+
+// driftVariables returns the supplied variables, normalized, as compared for
+// drift.
+func driftVariables(vars []v1alpha1.ProjectVariable, secret map[string]bool) []drift.Variable {
+	vars = normalize.ProjectVariables(vars)
+	out := make([]drift.Variable, len(vars))
+	for i, v := range vars {
+		out[i] = drift.Variable{Key: v.Key, Value: v.Value, Encrypted: v.Encrypted, Secret: secret[v.Key]}
+	}
+	return out
+}
+
+// variableHashes returns the hashes of the values of the supplied encrypted
+// variables, by key.
+func variableHashes(salt types.UID, vars []v1alpha1.ProjectVariable) map[string]string {
+	var hashes map[string]string
+	for _, v := range vars {
+		if !v.Encrypted {
+			continue
+		}
+		if hashes == nil {
+			hashes = map[string]string{}
+		}
+		hashes[v.Key] = helpers.VariableHash(salt, v.Key, v.Value)
+	}
+	return hashes
+}
+
+// lateInitialize fills the unset optional fields of the supplied parameters
+// with the values CodeFresh defaulted them to.
+func lateInitialize(in *v1alpha1.ProjectParameters, details *v1alpha1.ProjectDetails) {
+	in.ProjectImage = helpers.LateInitializeStringPtr(in.ProjectImage, details.ProjectImage)
+}
+
+// Create creates the project, or adopts an existing project with the same name
+// so that retrying a create whose ID was never recorded doesn't duplicate it.
+// The ID is returned through the external name, which the managed reconciler
+// persists together with its external-create-pending and -succeeded
+// annotations. If the provider stops before those are written, the reconciler
+// refuses to proceed until the external-create-pending annotation is removed;
+// doing so is safe, as the next Create adopts the project by name.
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.Project)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotProject)
 	}
 
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(constants.ErrExpectedCodeFreshClient)
+	existing, err := c.service.Projects().GetByName(ctx, cr.Spec.ForProvider.ProjectName)
+	switch {
+	case err == nil:
+		c.logger.Debug("Adopting existing project", "projectName", existing.ProjectName, "projectID", existing.ProjectID)
+		cr.Status.AtProvider.ProjectID = existing.ProjectID
+		meta.SetExternalName(cr, existing.ProjectID)
+		return managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{}}, nil
+	case !codefreshclient.IsNotFound(err):
+		return managed.ExternalCreation{}, errors.Wrap(err, errLookingUpProject)
 	}
 
-	var variables []v1alpha1.ProjectVariable //nolint:prealloc
-	for _, v := range cr.Spec.ForProvider.ProjectVariables {
-		variables = append(variables, v1alpha1.ProjectVariable{
-			Key:   v.Key,
-			Value: v.Value,
-		})
+	variables, err := c.resolveVariables(ctx, cr.Spec.ForProvider.ProjectVariables)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errResolvingVariables)
 	}
 
 	// Set up the parameters for project creation
@@ -213,22 +443,23 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		ProjectTags:      cr.Spec.ForProvider.ProjectTags,
 		ProjectVariables: variables,
 	}
+	if cr.Spec.ForProvider.ProjectImage != nil {
+		params.ProjectImage = *cr.Spec.ForProvider.ProjectImage
+	}
 
-	// Response struct to hold the created project's ID
-	var respData v1alpha1.CreateProjectResponse
-
-	// Use the generic CreateResource method
-	if err := c.service.CreateResource(ctx, "projects", params, &respData); err != nil {
+	respData, err := c.service.Projects().Create(ctx, params)
+	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreatingProject)
 	}
 
-	// Store the project ID in the status
+	// Store the project ID in the status and the external name
 	cr.Status.AtProvider.ProjectID = respData.ProjectID
-
-	// Update the status of the resource with the new project ID
-	if err := c.client.Status().Update(ctx, cr); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errUpdatingProjectStatus)
-	}
+	meta.SetExternalName(cr, respData.ProjectID)
+	// The managed reconciler may discard status set here, in which case the
+	// next Observe finds encrypted variables outdated and Update records
+	// their hashes again.
+	cr.Status.AtProvider.VariableHashes = variableHashes(cr.GetUID(), variables)
+	cr.Status.AtProvider.Owned = declaredEntries(cr)
 
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{},
@@ -245,24 +476,56 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(constants.ErrExpectedCodeFreshClient)
 	}
 
-	// Convert ProjectVariables to the expected format
-	var variables []v1alpha1.ProjectVariable //nolint:prealloc
-	for _, v := range cr.Spec.ForProvider.ProjectVariables {
-		variables = append(variables, v1alpha1.ProjectVariable{Key: v.Key, Value: v.Value})
+	// Read the values of variables from their Secrets and ConfigMaps
+	variables, err := c.resolveVariables(ctx, cr.Spec.ForProvider.ProjectVariables)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errResolvingVariables)
+	}
+
+	hashes := variableHashes(cr.GetUID(), variables)
+
+	tags := cr.Spec.ForProvider.ProjectTags
+	patchVariables := &variables
+	if isAdditive(cr) {
+		// The patch replaces tags and variables, so entries added elsewhere
+		// are sent back as CodeFresh returned them.
+		details, err := c.service.Projects().Get(ctx, cr.Status.AtProvider.ProjectID)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errGettingProject)
+		}
+		owned := ownedEntries(cr)
+		tags = helpers.MergeOwned(tags, details.ProjectTags, owned.Tags, helpers.Identity)
+		// CodeFresh only returns the masked value of encrypted variables, so
+		// those added elsewhere cannot be sent back. The variables are then
+		// left out of the patch if they are up to date, and not updated at
+		// all otherwise.
+		if foreign := foreignSecrets(cr, details.ProjectVariables); len(foreign) > 0 {
+			observed := helpers.OwnedOf(details.ProjectVariables, variableKeys(variables), owned.Variables, variableKey)
+			if len(variablesDrift(cr.Spec.ForProvider.ProjectVariables, variables, observed, cr.Status.AtProvider.VariableHashes, cr.GetUID())) > 0 {
+				err := errors.Errorf(errFmtForeignSecrets, strings.Join(foreign, ", "))
+				c.recorder.Event(cr, event.Warning(reasonVariablesUpdateRefused, err))
+				return managed.ExternalUpdate{}, err
+			}
+			patchVariables, hashes = nil, cr.Status.AtProvider.VariableHashes
+		} else {
+			variables = helpers.MergeOwned(variables, details.ProjectVariables, owned.Variables, variableKey)
+		}
 	}
 
 	// Define the update parameters including tags and variables
-	updateParams := map[string]interface{}{
-		"projectName": cr.Spec.ForProvider.ProjectName,
-		"tags":        cr.Spec.ForProvider.ProjectTags,
-		"variables":   variables,
+	patch := codefreshclient.ProjectPatch{
+		Name:      &cr.Spec.ForProvider.ProjectName,
+		Image:     cr.Spec.ForProvider.ProjectImage,
+		Tags:      &tags,
+		Variables: patchVariables,
 	}
 
 	// Update the resource
-	err := c.service.UpdateResource(ctx, "projects", cr.Status.AtProvider.ProjectID, updateParams, nil)
-	if err != nil {
+	if _, err := c.service.Projects().Patch(ctx, cr.Status.AtProvider.ProjectID, patch); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatingProject)
 	}
+	cr.Status.AtProvider.VariableHashes = hashes
+	cr.Status.AtProvider.Owned = declaredEntries(cr)
 
 	return managed.ExternalUpdate{}, nil
 }
@@ -277,11 +540,31 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(constants.ErrExpectedCodeFreshClient)
 	}
 
+	if cr.Spec.ForProvider.DeletePolicyForNonEmpty == v1alpha1.NonEmptyDeletePolicyRefuse {
+		projectDetails, err := c.service.Projects().Get(ctx, cr.Status.AtProvider.ProjectID)
+		if err != nil {
+			return errors.Wrap(err, errGettingProject)
+		}
+		if projectDetails.ProjectTotalPipelinesNumber > 0 {
+			err := errors.Errorf(errFmtProjectNotEmpty, projectDetails.ProjectTotalPipelinesNumber)
+			c.refuseDelete(cr, err)
+			return err
+		}
+	}
+
 	// Delete the resource
-	err := c.service.DeleteResource(ctx, "projects", cr.Status.AtProvider.ProjectID)
-	if err != nil {
+	if err := c.service.Projects().Delete(ctx, cr.Status.AtProvider.ProjectID); err != nil {
 		return errors.Wrap(err, errDeletingProject)
 	}
 
 	return nil
 }
+
+// refuseDelete reports why the project was not deleted as a DeleteRefused
+// condition, and as an event whenever the reason changes.
+func (c *external) refuseDelete(cr *v1alpha1.Project, reason error) {
+	if prev := cr.GetCondition(v1alpha1.TypeDeleteRefused); prev.Status != corev1.ConditionTrue || prev.Message != reason.Error() {
+		c.recorder.Event(cr, event.Warning(reasonDeleteRefused, reason))
+	}
+	cr.SetConditions(v1alpha1.DeleteRefused().WithMessage(reason.Error()))
+}
@@ -18,13 +18,28 @@ package project
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"crossplane-provider-codefresh/apis/resource/v1alpha1"
 	"crossplane-provider-codefresh/internal/client"
+	"crossplane-provider-codefresh/internal/client/fake"
+	"crossplane-provider-codefresh/internal/dryrun"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -38,6 +53,19 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+// recorder records the events it is sent.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
 func TestObserve(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -68,8 +96,8 @@ func TestObserve(t *testing.T) {
 				},
 			},
 			setup: func(m *client.MockCodeFreshAPIClient) {
-				m.MockGetResourceResponse = nil
-				m.MockGetResourceErr = client.ErrResourceNotFound
+				m.MockProjects.MockGetResponse = nil
+				m.MockProjects.MockGetErr = client.ErrResourceNotFound
 			},
 			want: want{
 				o: managed.ExternalObservation{
@@ -101,7 +129,7 @@ func TestObserve(t *testing.T) {
 				},
 			},
 			setup: func(m *client.MockCodeFreshAPIClient) {
-				m.MockGetResourceResponse = &v1alpha1.ProjectDetails{
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{
 					ProjectID:   "existing-project",
 					ProjectName: "TestProject",
 					ProjectTags: []string{"tag1", "tag2"},
@@ -110,7 +138,7 @@ func TestObserve(t *testing.T) {
 						{Key: "var2", Value: "value2"},
 					},
 				}
-				m.MockGetResourceErr = nil
+				m.MockProjects.MockGetErr = nil
 			},
 			want: want{
 				o: managed.ExternalObservation{
@@ -121,6 +149,140 @@ func TestObserve(t *testing.T) {
 				err: nil,
 			},
 		},
+		"ProjectImageLateInitialized": {
+			reason: "Should late-initialize the project image CodeFresh defaulted.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Project{
+					Spec: v1alpha1.ProjectSpec{
+						ForProvider: v1alpha1.ProjectParameters{
+							ProjectName: "TestProject",
+						},
+					},
+					Status: v1alpha1.ProjectStatus{
+						AtProvider: v1alpha1.ProjectObservation{
+							ProjectID: "existing-project",
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{
+					ProjectID:    "existing-project",
+					ProjectName:  "TestProject",
+					ProjectImage: "https://example.com/image.png",
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+					ConnectionDetails:       managed.ConnectionDetails{},
+				},
+				err: nil,
+			},
+		},
+		"ProjectImageOutdated": {
+			reason: "Should return ResourceUpToDate false when the project image differs.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Project{
+					Spec: v1alpha1.ProjectSpec{
+						ForProvider: v1alpha1.ProjectParameters{
+							ProjectName:  "TestProject",
+							ProjectImage: func() *string { s := "https://example.com/new.png"; return &s }(),
+						},
+					},
+					Status: v1alpha1.ProjectStatus{
+						AtProvider: v1alpha1.ProjectObservation{
+							ProjectID: "existing-project",
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{
+					ProjectID:    "existing-project",
+					ProjectName:  "TestProject",
+					ProjectImage: "https://example.com/image.png",
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+				err: nil,
+			},
+		},
+		"ProjectObservedByExternalName": {
+			reason: "Should observe an existing project identified only by its external name.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "inventory",
+						Annotations: map[string]string{meta.AnnotationKeyExternalName: "existing-project"},
+					},
+					Spec: v1alpha1.ProjectSpec{
+						ForProvider: v1alpha1.ProjectParameters{
+							ProjectName: "TestProject",
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{
+					ProjectID:   "existing-project",
+					ProjectName: "TestProject",
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+				err: nil,
+			},
+		},
+		"NonEmptyProjectOrphaned": {
+			reason: "Should report a deleted non-empty project as gone when it should be orphaned.",
+			args: args{
+				ctx: context.TODO(),
+				mg: &v1alpha1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						DeletionTimestamp: &metav1.Time{Time: time.Now()},
+					},
+					Spec: v1alpha1.ProjectSpec{
+						ForProvider: v1alpha1.ProjectParameters{
+							ProjectName:             "TestProject",
+							DeletePolicyForNonEmpty: v1alpha1.NonEmptyDeletePolicyOrphan,
+						},
+					},
+					Status: v1alpha1.ProjectStatus{
+						AtProvider: v1alpha1.ProjectObservation{
+							ProjectID: "existing-project",
+						},
+					},
+				},
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{
+					ProjectID:                   "existing-project",
+					ProjectName:                 "TestProject",
+					ProjectTotalPipelinesNumber: 2,
+				}
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists: false,
+				},
+				err: nil,
+			},
+		},
 		// Add more test cases as needed.
 	}
 
@@ -128,7 +290,7 @@ func TestObserve(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			mockClient := &client.MockCodeFreshAPIClient{}
 			tc.setup(mockClient)
-			e := external{service: mockClient}
+			e := external{service: mockClient, recorder: event.NewNopRecorder()}
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -139,3 +301,571 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		id  string
+		err error
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		setup  func(*client.MockCodeFreshAPIClient)
+		want   want
+	}{
+		"AdoptExisting": {
+			reason: "Should adopt a project that already exists with the same name instead of creating another.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetByNameResponse = &v1alpha1.ProjectDetails{ProjectID: "existing-project", ProjectName: "TestProject"}
+				m.MockProjects.MockCreateErr = errors.New("should not be called")
+			},
+			want: want{id: "existing-project"},
+		},
+		"CreateNew": {
+			reason: "Should create the project when none exists with the same name.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetByNameErr = client.ErrResourceNotFound
+				m.MockProjects.MockCreateResponse = &v1alpha1.CreateProjectResponse{ProjectID: "new-project"}
+			},
+			want: want{id: "new-project"},
+		},
+		"LookupFailed": {
+			reason: "Should not create the project when the lookup by name fails for another reason.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetByNameErr = errBoom
+			},
+			want: want{err: errors.Wrap(errBoom, errLookingUpProject)},
+		},
+		"CreateFailed": {
+			reason: "Should return an error when CodeFresh fails to create the project.",
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetByNameErr = client.ErrResourceNotFound
+				m.MockProjects.MockCreateErr = errBoom
+			},
+			want: want{err: errors.Wrap(errBoom, errCreatingProject)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &client.MockCodeFreshAPIClient{}
+			tc.setup(mockClient)
+			e := external{service: mockClient, logger: logging.NewNopLogger(), recorder: event.NewNopRecorder()}
+			cr := &v1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "project"},
+				Spec:       v1alpha1.ProjectSpec{ForProvider: v1alpha1.ProjectParameters{ProjectName: "TestProject"}},
+			}
+			meta.SetExternalName(cr, cr.GetName())
+			_, err := e.Create(context.TODO(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.id, meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, cr.Status.AtProvider.ProjectID); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want project ID, +got project ID:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	project := func(policy v1alpha1.NonEmptyDeletePolicy) *v1alpha1.Project {
+		return &v1alpha1.Project{
+			Spec: v1alpha1.ProjectSpec{
+				ForProvider: v1alpha1.ProjectParameters{
+					ProjectName:             "TestProject",
+					DeletePolicyForNonEmpty: policy,
+				},
+			},
+			Status: v1alpha1.ProjectStatus{
+				AtProvider: v1alpha1.ProjectObservation{
+					ProjectID: "existing-project",
+				},
+			},
+		}
+	}
+
+	type want struct {
+		err    error
+		events []event.Event
+		cond   xpv1.Condition
+	}
+
+	refused := errors.Errorf(errFmtProjectNotEmpty, 2)
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		setup  func(*client.MockCodeFreshAPIClient)
+		want   want
+	}{
+		"NonEmptyProjectDeleted": {
+			reason: "Should delete a non-empty project by default.",
+			args: args{
+				ctx: context.TODO(),
+				mg:  project(""),
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{ProjectTotalPipelinesNumber: 2}
+			},
+			want: want{cond: xpv1.Condition{Type: v1alpha1.TypeDeleteRefused, Status: corev1.ConditionUnknown}},
+		},
+		"NonEmptyProjectRefused": {
+			reason: "Should refuse to delete a non-empty project when asked to, and report why in a condition and an event.",
+			args: args{
+				ctx: context.TODO(),
+				mg:  project(v1alpha1.NonEmptyDeletePolicyRefuse),
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{ProjectTotalPipelinesNumber: 2}
+			},
+			want: want{
+				err:    refused,
+				events: []event.Event{event.Warning(reasonDeleteRefused, refused)},
+				cond:   v1alpha1.DeleteRefused().WithMessage(refused.Error()),
+			},
+		},
+		"EmptyProjectDeleted": {
+			reason: "Should delete an empty project even when refusing non-empty ones.",
+			args: args{
+				ctx: context.TODO(),
+				mg:  project(v1alpha1.NonEmptyDeletePolicyRefuse),
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockGetResponse = &v1alpha1.ProjectDetails{}
+			},
+			want: want{cond: xpv1.Condition{Type: v1alpha1.TypeDeleteRefused, Status: corev1.ConditionUnknown}},
+		},
+		"DeleteFailed": {
+			reason: "Should return an error when CodeFresh fails to delete the project.",
+			args: args{
+				ctx: context.TODO(),
+				mg:  project(""),
+			},
+			setup: func(m *client.MockCodeFreshAPIClient) {
+				m.MockProjects.MockDeleteErr = errors.New("boom")
+			},
+			want: want{
+				err:  errors.Wrap(errors.New("boom"), errDeletingProject),
+				cond: xpv1.Condition{Type: v1alpha1.TypeDeleteRefused, Status: corev1.ConditionUnknown},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &client.MockCodeFreshAPIClient{}
+			tc.setup(mockClient)
+			r := &recorder{}
+			e := external{service: mockClient, recorder: r}
+			err := e.Delete(tc.args.ctx, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+			got := tc.args.mg.(*v1alpha1.Project).GetCondition(v1alpha1.TypeDeleteRefused)
+			if diff := cmp.Diff(tc.want.cond, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLifecycleAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	e := external{
+		client:   &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Project{
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{
+				ProjectName: "project",
+				ProjectTags: []string{"a"},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+
+	// A retried create, for example after the ID failed to persist, adopts
+	// the project rather than creating a duplicate.
+	retry := cr.DeepCopy()
+	retry.Status.AtProvider.ProjectID = ""
+	if _, err := e.Create(ctx, retry); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	if retry.Status.AtProvider.ProjectID != cr.Status.AtProvider.ProjectID {
+		t.Errorf("e.Create(...): want adopted project %q, got %q", cr.Status.AtProvider.ProjectID, retry.Status.AtProvider.ProjectID)
+	}
+	if page, err := e.service.Projects().List(ctx, client.ProjectListOptions{}); err != nil || page.Total != 1 {
+		t.Errorf("e.Create(...): want 1 project, got %+v (%v)", page, err)
+	}
+
+	cr.Spec.ForProvider.ProjectTags = []string{"a", "b"}
+	got, err := e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if !got.ResourceExists || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want existing, outdated project, got %+v", got)
+	}
+
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	got, err = e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date project after update, got %+v", got)
+	}
+
+	if err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("e.Delete(...): %v", err)
+	}
+	if _, ok := srv.Project(cr.Status.AtProvider.ProjectID); ok {
+		t.Error("e.Delete(...): project still exists")
+	}
+}
+
+func TestVariablesFromSourcesAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	token := "secret-1"
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ kclient.ObjectKey, obj kclient.Object) error {
+			switch o := obj.(type) {
+			case *corev1.Secret:
+				o.Data = map[string][]byte{"token": []byte(token)}
+			case *corev1.ConfigMap:
+				o.Data = map[string]string{"region": "eu-west-1"}
+			}
+			return nil
+		},
+	}
+	e := external{
+		client:   kube,
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{UID: "uid"},
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{
+				ProjectName: "project",
+				ProjectVariables: []v1alpha1.ProjectVariable{
+					{Key: "TOKEN", Encrypted: true, ValueFrom: &v1alpha1.VariableSource{
+						SecretKeyRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "creds", Namespace: "default"}, Key: "token"},
+					}},
+					{Key: "REGION", ValueFrom: &v1alpha1.VariableSource{
+						ConfigMapKeyRef: &v1alpha1.ConfigMapKeySelector{Name: "settings", Namespace: "default", Key: "region"},
+					}},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+	p, _ := srv.Project(cr.Status.AtProvider.ProjectID)
+	want := []v1alpha1.ProjectVariable{
+		{Key: "TOKEN", Value: fake.MaskedValue, Encrypted: true},
+		{Key: "REGION", Value: "eu-west-1"},
+	}
+	if diff := cmp.Diff(want, p.ProjectVariables); diff != "" {
+		t.Errorf("e.Create(...): -want variables, +got variables:\n%s", diff)
+	}
+
+	// The managed reconciler discards status set by Create, so the hash of
+	// the encrypted variable is only recorded by the Update that follows.
+	cr.Status.AtProvider.VariableHashes = nil
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated project, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date project, got %+v (%v)", got, err)
+	}
+
+	// A changed secret is drift, although CodeFresh masks the value.
+	token = "secret-2"
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated project, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date project, got %+v (%v)", got, err)
+	}
+
+	kube.MockGet = test.NewMockGetFn(errors.New("boom"))
+	if _, err := e.Observe(ctx, cr); err == nil {
+		t.Error("e.Observe(...): want error for a secret that cannot be read")
+	}
+}
+
+func TestAdditiveManagementAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	e := external{
+		client:   &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil)},
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+	cr := &v1alpha1.Project{
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{
+				ProjectName:      "project",
+				ProjectTags:      []string{"a"},
+				ProjectVariables: []v1alpha1.ProjectVariable{{Key: "A", Value: "a"}},
+				ManagementMode:   v1alpha1.ManagementModeAdditive,
+			},
+		},
+	}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+
+	// Tags and variables added in the CodeFresh UI are not drift.
+	tags := []string{"a", "ui"}
+	vars := []v1alpha1.ProjectVariable{{Key: "A", Value: "a"}, {Key: "UI", Value: "ui"}, {Key: "UI_SECRET", Value: "secret", Encrypted: true}}
+	if _, err := e.service.Projects().Patch(ctx, cr.Status.AtProvider.ProjectID, client.ProjectPatch{Tags: &tags, Variables: &vars}); err != nil {
+		t.Fatalf("Patch(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want up to date project, got %+v (%v)", got, err)
+	}
+	if diff := cmp.Diff(&v1alpha1.OwnedEntries{Tags: []string{"a"}, Variables: []string{"A"}}, cr.Status.AtProvider.Owned); diff != "" {
+		t.Errorf("e.Observe(...): -want owned, +got owned:\n%s", diff)
+	}
+
+	// Owned entries that are no longer declared are removed, others kept.
+	// The variables are up to date, so they are left out of the patch and
+	// the encrypted variable added in the UI is kept.
+	cr.Spec.ForProvider.ProjectTags = []string{"b"}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated project, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	p, _ := srv.Project(cr.Status.AtProvider.ProjectID)
+	if diff := cmp.Diff([]string{"b", "ui"}, p.ProjectTags); diff != "" {
+		t.Errorf("e.Update(...): -want tags, +got tags:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"A", "UI", "UI_SECRET"}, variableKeys(p.ProjectVariables)); diff != "" {
+		t.Errorf("e.Update(...): -want variables, +got variables:\n%s", diff)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Errorf("e.Observe(...): want up to date project, got %+v (%v)", got, err)
+	}
+
+	// Variables cannot be updated without removing the encrypted variable
+	// added in the UI, so the update is refused.
+	cr.Spec.ForProvider.ProjectVariables = []v1alpha1.ProjectVariable{{Key: "B", Value: "b"}}
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated project, got %+v (%v)", got, err)
+	}
+	r := &recorder{}
+	e.recorder = r
+	wantErr := errors.Errorf(errFmtForeignSecrets, "UI_SECRET")
+	if _, err := e.Update(ctx, cr); !cmp.Equal(wantErr, err, test.EquateErrors()) {
+		t.Fatalf("e.Update(...): want error %v, got %v", wantErr, err)
+	}
+	if diff := cmp.Diff([]event.Event{event.Warning(reasonVariablesUpdateRefused, wantErr)}, r.events, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Update(...): -want events, +got events:\n%s", diff)
+	}
+	p, _ = srv.Project(cr.Status.AtProvider.ProjectID)
+	if diff := cmp.Diff([]string{"A", "UI", "UI_SECRET"}, variableKeys(p.ProjectVariables)); diff != "" {
+		t.Errorf("e.Update(...): -want variables, +got variables:\n%s", diff)
+	}
+
+	// In Authoritative mode the provider owns every entry.
+	cr.Spec.ForProvider.ManagementMode = v1alpha1.ManagementModeAuthoritative
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated project, got %+v (%v)", got, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if p, _ := srv.Project(cr.Status.AtProvider.ProjectID); !cmp.Equal([]string{"b"}, p.ProjectTags) || cr.Status.AtProvider.Owned != nil {
+		t.Errorf("e.Update(...): want only declared tags and no owned entries, got %v and %+v", p.ProjectTags, cr.Status.AtProvider.Owned)
+	}
+}
+
+func TestDriftAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	token := "secret-1"
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ kclient.ObjectKey, obj kclient.Object) error {
+			if s, ok := obj.(*corev1.Secret); ok {
+				s.Data = map[string][]byte{"token": []byte(token)}
+			}
+			return nil
+		},
+	}
+	r := &recorder{}
+	e := external{
+		client:   kube,
+		service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+		logger:   logging.NewNopLogger(),
+		recorder: r,
+	}
+	cr := &v1alpha1.Project{
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{
+				ProjectName: "project",
+				ProjectTags: []string{"a"},
+				ProjectVariables: []v1alpha1.ProjectVariable{
+					{Key: "REGION", Value: "eu-west-1"},
+					{Key: "TOKEN", ValueFrom: &v1alpha1.VariableSource{
+						SecretKeyRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "creds", Namespace: "default"}, Key: "token"},
+					}},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("e.Create(...): %v", err)
+	}
+
+	cr.Spec.ForProvider.ProjectTags = []string{"b", "a"}
+	cr.Spec.ForProvider.ProjectVariables[0].Value = "us-east-1"
+	token = "secret-2"
+	if got, err := e.Observe(ctx, cr); err != nil || got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want outdated project, got %+v (%v)", got, err)
+	}
+	want := `projectTags: want ["a","b"], got ["a"]; ` +
+		`projectVariables[REGION]: want "us-east-1", got "eu-west-1"; ` +
+		`projectVariables[TOKEN]: want <redacted>, got <redacted>`
+	if diff := cmp.Diff(want, cr.Status.AtProvider.Drift); diff != "" {
+		t.Errorf("e.Observe(...): -want drift, +got drift:\n%s", diff)
+	}
+	if strings.Contains(cr.Status.AtProvider.Drift, "secret-") {
+		t.Errorf("e.Observe(...): drift reveals a secret value: %s", cr.Status.AtProvider.Drift)
+	}
+	if diff := cmp.Diff(v1alpha1.Drifted().WithMessage(want), cr.GetCondition(v1alpha1.TypeDrifted), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Observe(...): -want condition, +got condition:\n%s", diff)
+	}
+
+	// The same drift is only reported once.
+	if _, err := e.Observe(ctx, cr); err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if diff := cmp.Diff([]event.Event{event.Normal(reasonDriftDetected, want)}, r.events); diff != "" {
+		t.Errorf("e.Observe(...): -want events, +got events:\n%s", diff)
+	}
+
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("e.Update(...): %v", err)
+	}
+	if got, err := e.Observe(ctx, cr); err != nil || !got.ResourceUpToDate {
+		t.Fatalf("e.Observe(...): want up to date project, got %+v (%v)", got, err)
+	}
+	if cr.Status.AtProvider.Drift != "" {
+		t.Errorf("e.Observe(...): want drift cleared, got %q", cr.Status.AtProvider.Drift)
+	}
+	if diff := cmp.Diff(v1alpha1.NotDrifted(), cr.GetCondition(v1alpha1.TypeDrifted), test.EquateConditions()); diff != "" {
+		t.Errorf("e.Observe(...): -want condition, +got condition:\n%s", diff)
+	}
+}
+
+func TestDryRunAgainstFakeServer(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	r := &recorder{}
+	c := dryrun.NewConnecter(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &external{
+			service:  client.NewCodeFreshAPIClient("", srv.URL, logging.NewNopLogger()),
+			logger:   logging.NewNopLogger(),
+			recorder: r,
+		}, nil
+	}), v1alpha1.ProjectKind, false, r)
+	cr := &v1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1alpha1.AnnotationKeyDryRun: "true"}},
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{ProjectName: "project", ProjectTags: []string{"a"}},
+		},
+	}
+	ctx := context.Background()
+
+	observe := func(want v1alpha1.PlannedAction) {
+		t.Helper()
+		e, err := c.Connect(ctx, cr)
+		if err != nil {
+			t.Fatalf("c.Connect(...): %v", err)
+		}
+		if _, err := e.Observe(ctx, cr); err != nil {
+			t.Fatalf("e.Observe(...): %v", err)
+		}
+		if diff := cmp.Diff(want, cr.Status.AtProvider.PlannedAction); diff != "" {
+			t.Errorf("e.Observe(...): -want planned action, +got planned action:\n%s", diff)
+		}
+	}
+
+	observe(v1alpha1.PlannedActionCreate)
+
+	id := srv.AddProject(v1alpha1.ProjectDetails{ProjectName: "project", ProjectTags: []string{"b"}})
+	meta.SetExternalName(cr, id)
+	observe(v1alpha1.PlannedActionUpdate)
+
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	observe(v1alpha1.PlannedActionDelete)
+
+	for _, req := range srv.Requests() {
+		if req.Method != "GET" {
+			t.Errorf("e.Observe(...): want no writes in dry-run mode, got %s %s", req.Method, req.Path)
+		}
+	}
+	if p, ok := srv.Project(id); !ok || !cmp.Equal(p.ProjectTags, []string{"b"}) {
+		t.Errorf("srv.Project(...): want project left unchanged, got %+v (exists: %t)", p, ok)
+	}
+	want := []event.Event{
+		event.Normal("PlannedCreate", "Dry run: would create the project in CodeFresh"),
+		event.Normal("PlannedUpdate", "Dry run: would update the project in CodeFresh"),
+		event.Normal("PlannedDelete", "Dry run: would delete the project in CodeFresh"),
+	}
+	if diff := cmp.Diff(want, r.events, cmpopts.IgnoreSliceElements(func(e event.Event) bool { return e.Reason == reasonDriftDetected })); diff != "" {
+		t.Errorf("e.Observe(...): -want events, +got events:\n%s", diff)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift describes how a resource in CodeFresh differs from the spec
+// of its managed resource, in a form fit for events and status.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Redacted is shown in place of values that must not be revealed, such
+	// as those of encrypted variables and of variables read from a Secret.
+	Redacted = "<redacted>"
+
+	// Unset is shown for a value that is not set.
+	Unset = "<unset>"
+
+	// MaxSummaryLength is the maximum length, in bytes, of a summary recorded
+	// in status or sent in an event.
+	MaxSummaryLength = 1024
+)
+
+// A Difference is a field whose desired and observed values differ.
+type Difference struct {
+	// Path of the field, relative to spec.forProvider.
+	Path string
+
+	// Desired value of the field, as declared in the spec.
+	Desired string
+
+	// Observed value of the field, as returned by CodeFresh.
+	Observed string
+}
+
+// String returns the difference as "path: want desired, got observed".
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: want %s, got %s", d.Path, d.Desired, d.Observed)
+}
+
+// Field returns the difference at path between the supplied desired and
+// observed values, each formatted by Format.
+func Field(path string, desired, observed any) Difference {
+	return Difference{Path: path, Desired: Format(desired), Observed: Format(observed)}
+}
+
+// Format returns v as compact JSON. Nil pointers and empty lists and maps are
+// formatted as Unset.
+func Format(v any) string {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return Unset
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return Unset
+		}
+	case reflect.Slice, reflect.Map:
+		if rv.Len() == 0 {
+			return Unset
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// A Variable is a variable of a Project or Pipeline, as compared for drift.
+type Variable struct {
+	Key       string
+	Value     string
+	Encrypted bool
+
+	// Secret is true if the value must not be revealed, for example because
+	// it was read from a Secret.
+	Secret bool
+}
+
+// Variables returns a difference for each key whose desired and observed
+// variables differ, in key order. Variables with the same key are compared
+// pairwise, in the order supplied, by same. The values of a key are redacted
+// on both sides if any of its variables is encrypted or secret.
+func Variables(path string, desired, observed []Variable, same func(desired, observed Variable) bool) []Difference {
+	want, got := byKey(desired), byKey(observed)
+	keys := make([]string, 0, len(want)+len(got))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var out []Difference
+	for _, k := range keys {
+		w, g := want[k], got[k]
+		if isSame(w, g, same) {
+			continue
+		}
+		redact := isSensitive(w) || isSensitive(g)
+		out = append(out, Difference{
+			Path:     fmt.Sprintf("%s[%s]", path, k),
+			Desired:  formatVariables(w, redact),
+			Observed: formatVariables(g, redact),
+		})
+	}
+	return out
+}
+
+func byKey(vars []Variable) map[string][]Variable {
+	out := map[string][]Variable{}
+	for _, v := range vars {
+		out[v.Key] = append(out[v.Key], v)
+	}
+	return out
+}
+
+func isSame(want, got []Variable, same func(desired, observed Variable) bool) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if !same(want[i], got[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSensitive(vars []Variable) bool {
+	for _, v := range vars {
+		if v.Encrypted || v.Secret {
+			return true
+		}
+	}
+	return false
+}
+
+func formatVariables(vars []Variable, redact bool) string {
+	if len(vars) == 0 {
+		return Unset
+	}
+	values := make([]string, len(vars))
+	for i, v := range vars {
+		values[i] = strconv.Quote(v.Value)
+		if redact {
+			values[i] = Redacted
+		}
+		if v.Encrypted {
+			values[i] += " (encrypted)"
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// Summary returns the supplied differences separated by "; ", truncated to at
+// most max bytes. A truncated summary ends with "...".
+func Summary(diffs []Difference, max int) string {
+	parts := make([]string, len(diffs))
+	for i, d := range diffs {
+		parts[i] = d.String()
+	}
+	return truncate(strings.Join(parts, "; "), max)
+}
+
+// truncate returns s cut to at most max bytes, without splitting a rune.
+func truncate(s string, max int) string {
+	const ellipsis = "..."
+	if len(s) <= max {
+		return s
+	}
+	if max < len(ellipsis) {
+		return ellipsis[:max]
+	}
+	cut := max - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
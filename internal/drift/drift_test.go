@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormat(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		v      any
+		want   string
+	}{
+		"Nil": {
+			reason: "An unset value should be formatted as Unset.",
+			v:      nil,
+			want:   Unset,
+		},
+		"NilPointer": {
+			reason: "A nil pointer should be formatted as Unset.",
+			v:      (*int)(nil),
+			want:   Unset,
+		},
+		"EmptyList": {
+			reason: "An empty list should be formatted as Unset.",
+			v:      []string{},
+			want:   Unset,
+		},
+		"Pointer": {
+			reason: "A pointer should be formatted as the value it points to.",
+			v:      func() *int { i := 3; return &i }(),
+			want:   "3",
+		},
+		"List": {
+			reason: "A list should be formatted as compact JSON.",
+			v:      []string{"a", "b"},
+			want:   `["a","b"]`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Format(tc.v)); diff != "" {
+				t.Errorf("\n%s\nFormat(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	same := func(w, o Variable) bool { return w.Value == o.Value && w.Encrypted == o.Encrypted }
+
+	cases := map[string]struct {
+		reason   string
+		desired  []Variable
+		observed []Variable
+		want     []Difference
+	}{
+		"Same": {
+			reason:   "Variables that are the same should not differ.",
+			desired:  []Variable{{Key: "A", Value: "a"}},
+			observed: []Variable{{Key: "A", Value: "a"}},
+		},
+		"ChangedMissingAndExtra": {
+			reason:   "A changed, missing and extra variable should each differ, in key order.",
+			desired:  []Variable{{Key: "C", Value: "c"}, {Key: "A", Value: "a"}},
+			observed: []Variable{{Key: "A", Value: "x"}, {Key: "B", Value: "b"}},
+			want: []Difference{
+				{Path: "vars[A]", Desired: `"a"`, Observed: `"x"`},
+				{Path: "vars[B]", Desired: Unset, Observed: `"b"`},
+				{Path: "vars[C]", Desired: `"c"`, Observed: Unset},
+			},
+		},
+		"Secret": {
+			reason:   "The values of a secret variable should be redacted on both sides.",
+			desired:  []Variable{{Key: "TOKEN", Value: "new", Secret: true}},
+			observed: []Variable{{Key: "TOKEN", Value: "old"}},
+			want:     []Difference{{Path: "vars[TOKEN]", Desired: Redacted, Observed: Redacted}},
+		},
+		"Encrypted": {
+			reason:   "The values of an encrypted variable should be redacted on both sides.",
+			desired:  []Variable{{Key: "TOKEN", Value: "new", Encrypted: true}},
+			observed: []Variable{{Key: "TOKEN", Value: "old"}},
+			want:     []Difference{{Path: "vars[TOKEN]", Desired: Redacted + " (encrypted)", Observed: Redacted}},
+		},
+		"Duplicate": {
+			reason:   "A duplicated variable should differ from a single one.",
+			desired:  []Variable{{Key: "A", Value: "a"}},
+			observed: []Variable{{Key: "A", Value: "a"}, {Key: "A", Value: "a"}},
+			want:     []Difference{{Path: "vars[A]", Desired: `"a"`, Observed: `"a", "a"`}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Variables("vars", tc.desired, tc.observed, same)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nVariables(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	diffs := []Difference{
+		{Path: "a", Desired: "1", Observed: "2"},
+		{Path: "b", Desired: `"ü"`, Observed: Unset},
+	}
+
+	cases := map[string]struct {
+		reason string
+		max    int
+		want   string
+	}{
+		"Full": {
+			reason: "A summary that fits should not be truncated.",
+			max:    MaxSummaryLength,
+			want:   `a: want 1, got 2; b: want "ü", got <unset>`,
+		},
+		"Truncated": {
+			reason: "A summary that does not fit should be truncated with an ellipsis.",
+			max:    20,
+			want:   "a: want 1, got 2;...",
+		},
+		"RuneBoundary": {
+			reason: "A summary should not be truncated within a rune.",
+			max:    31,
+			want:   `a: want 1, got 2; b: want "...`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Summary(diffs, tc.max)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSummary(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if len(got) > tc.max || !utf8.ValidString(got) {
+				t.Errorf("\n%s\nSummary(...): want at most %d bytes of valid UTF-8, got %q", tc.reason, tc.max, got)
+			}
+		})
+	}
+
+	long := make([]Difference, 100)
+	for i := range long {
+		long[i] = Field(strings.Repeat("x", 20), i, nil)
+	}
+	if got := Summary(long, MaxSummaryLength); len(got) > MaxSummaryLength {
+		t.Errorf("Summary(...): want at most %d bytes, got %d", MaxSummaryLength, len(got))
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun plans the changes the provider would make to resources in
+// CodeFresh without making them. A resource is in dry-run mode if the
+// provider runs with the DryRun feature enabled, or if the resource has the
+// dry-run annotation.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// PlannedActions counts the actions planned for resources in dry-run mode,
+// by kind and action. An action is counted when it is first planned for a
+// resource, not each time the resource is observed.
+var PlannedActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "codefresh",
+	Subsystem: "dry_run",
+	Name:      "planned_actions_total",
+	Help:      "Number of actions planned, but not performed, for resources in dry-run mode.",
+}, []string{"kind", "action"})
+
+func init() {
+	metrics.Registry.MustRegister(PlannedActions)
+}
+
+// A Planner is a managed resource that records the action the provider
+// plans for it in dry-run mode.
+type Planner interface {
+	resource.Managed
+
+	GetPlannedAction() v1alpha1.PlannedAction
+	SetPlannedAction(a v1alpha1.PlannedAction)
+}
+
+// Enabled returns true if the supplied resource is in dry-run mode, either
+// because dry-run mode is enabled globally or because of its annotation.
+func Enabled(global bool, mg resource.Managed) bool {
+	return global || strings.EqualFold(mg.GetAnnotations()[v1alpha1.AnnotationKeyDryRun], "true")
+}
+
+// Plan returns the action the managed reconciler would take given the
+// supplied observation of mg.
+func Plan(mg resource.Managed, o managed.ExternalObservation) v1alpha1.PlannedAction {
+	switch {
+	case meta.WasDeleted(mg):
+		if o.ResourceExists && mg.GetDeletionPolicy() != xpv1.DeletionOrphan {
+			return v1alpha1.PlannedActionDelete
+		}
+	case !o.ResourceExists:
+		return v1alpha1.PlannedActionCreate
+	case !o.ResourceUpToDate:
+		return v1alpha1.PlannedActionUpdate
+	}
+	return v1alpha1.PlannedActionNone
+}
+
+// A Connecter wraps an ExternalConnecter so that the clients it connects
+// plan, rather than make, changes to resources in dry-run mode.
+type Connecter struct {
+	managed.ExternalConnecter
+
+	kind     string
+	global   bool
+	recorder event.Recorder
+}
+
+// NewConnecter returns a Connecter for resources of the supplied kind. All
+// resources are in dry-run mode if global is true.
+func NewConnecter(c managed.ExternalConnecter, kind string, global bool, r event.Recorder) *Connecter {
+	return &Connecter{ExternalConnecter: c, kind: kind, global: global, recorder: r}
+}
+
+// Connect returns the wrapped client, or a client that plans changes if mg
+// is in dry-run mode.
+func (c *Connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	if !Enabled(c.global, mg) {
+		// A resource that left dry-run mode has no planned action.
+		if p, ok := mg.(Planner); ok {
+			p.SetPlannedAction("")
+		}
+		return ec, nil
+	}
+	return &external{ExternalClient: ec, kind: c.kind, recorder: c.recorder}, nil
+}
+
+// An external observes resources in CodeFresh through the wrapped client,
+// but never changes them.
+type external struct {
+	managed.ExternalClient
+
+	kind     string
+	recorder event.Recorder
+}
+
+// Observe records the action the wrapped client's observation calls for, and
+// reports that none is needed so that the managed reconciler makes no
+// change. A resource that is being deleted is reported as gone, so that it is
+// deleted from the API server but left in place in CodeFresh.
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+	e.record(mg, Plan(mg, o))
+
+	if meta.WasDeleted(mg) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        true,
+		ResourceLateInitialized: o.ResourceExists && o.ResourceLateInitialized,
+		ConnectionDetails:       o.ConnectionDetails,
+	}, nil
+}
+
+// Create records that the resource would be created. It is not expected to
+// be called, since Observe reports that the resource exists.
+func (e *external) Create(_ context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	e.record(mg, v1alpha1.PlannedActionCreate)
+	return managed.ExternalCreation{}, nil
+}
+
+// Update records that the resource would be updated. It is not expected to
+// be called, since Observe reports that the resource is up to date.
+func (e *external) Update(_ context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	e.record(mg, v1alpha1.PlannedActionUpdate)
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete records that the resource would be deleted. It is not expected to
+// be called, since Observe reports that a deleted resource is gone.
+func (e *external) Delete(_ context.Context, mg resource.Managed) error {
+	e.record(mg, v1alpha1.PlannedActionDelete)
+	return nil
+}
+
+// record sets the planned action of mg. An action other than None is sent as
+// an event and counted when it differs from the one last planned, to avoid
+// repeating it each time the resource is observed.
+func (e *external) record(mg resource.Managed, a v1alpha1.PlannedAction) {
+	if p, ok := mg.(Planner); ok {
+		if p.GetPlannedAction() == a {
+			return
+		}
+		p.SetPlannedAction(a)
+	}
+	if a == v1alpha1.PlannedActionNone {
+		return
+	}
+	PlannedActions.WithLabelValues(e.kind, string(a)).Inc()
+	e.recorder.Event(mg, event.Normal(event.Reason("Planned"+string(a)),
+		fmt.Sprintf("Dry run: would %s the %s in CodeFresh", strings.ToLower(string(a)), strings.ToLower(e.kind))))
+}
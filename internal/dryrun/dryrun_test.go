@@ -0,0 +1,328 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
+type projectModifier func(*v1alpha1.Project)
+
+func project(m ...projectModifier) *v1alpha1.Project {
+	cr := &v1alpha1.Project{}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func withDryRunAnnotation(v string) projectModifier {
+	return func(cr *v1alpha1.Project) {
+		cr.SetAnnotations(map[string]string{v1alpha1.AnnotationKeyDryRun: v})
+	}
+}
+
+func withDeletionTimestamp() projectModifier {
+	return func(cr *v1alpha1.Project) {
+		now := metav1.Now()
+		cr.SetDeletionTimestamp(&now)
+	}
+}
+
+func withDeletionPolicy(p xpv1.DeletionPolicy) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.SetDeletionPolicy(p) }
+}
+
+func withPlannedAction(a v1alpha1.PlannedAction) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Status.AtProvider.PlannedAction = a }
+}
+
+func TestEnabled(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		global bool
+		mg     resource.Managed
+		want   bool
+	}{
+		"Global": {
+			reason: "Every resource should be in dry-run mode if it is enabled globally.",
+			global: true,
+			mg:     project(),
+			want:   true,
+		},
+		"Annotated": {
+			reason: "A resource annotated for dry-run should be in dry-run mode.",
+			mg:     project(withDryRunAnnotation("True")),
+			want:   true,
+		},
+		"AnnotatedFalse": {
+			reason: "A resource annotated with a value other than true should not be in dry-run mode.",
+			mg:     project(withDryRunAnnotation("false")),
+		},
+		"NotAnnotated": {
+			reason: "A resource without the annotation should not be in dry-run mode.",
+			mg:     project(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Enabled(tc.global, tc.mg); got != tc.want {
+				t.Errorf("\n%s\nEnabled(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		o      managed.ExternalObservation
+		want   v1alpha1.PlannedAction
+	}{
+		"Create": {
+			reason: "A resource that does not exist should be created.",
+			mg:     project(),
+			want:   v1alpha1.PlannedActionCreate,
+		},
+		"Update": {
+			reason: "A resource that is not up to date should be updated.",
+			mg:     project(),
+			o:      managed.ExternalObservation{ResourceExists: true},
+			want:   v1alpha1.PlannedActionUpdate,
+		},
+		"None": {
+			reason: "A resource that is up to date should be left alone.",
+			mg:     project(),
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want:   v1alpha1.PlannedActionNone,
+		},
+		"Delete": {
+			reason: "A deleted resource that exists should be deleted.",
+			mg:     project(withDeletionTimestamp()),
+			o:      managed.ExternalObservation{ResourceExists: true},
+			want:   v1alpha1.PlannedActionDelete,
+		},
+		"AlreadyDeleted": {
+			reason: "A deleted resource that no longer exists should be left alone.",
+			mg:     project(withDeletionTimestamp()),
+			want:   v1alpha1.PlannedActionNone,
+		},
+		"Orphaned": {
+			reason: "A deleted resource with the Orphan deletion policy should be left alone.",
+			mg:     project(withDeletionTimestamp(), withDeletionPolicy(xpv1.DeletionOrphan)),
+			o:      managed.ExternalObservation{ResourceExists: true},
+			want:   v1alpha1.PlannedActionNone,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Plan(tc.mg, tc.o)); diff != "" {
+				t.Errorf("\n%s\nPlan(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConnecter(t *testing.T) {
+	type want struct {
+		o       managed.ExternalObservation
+		planned v1alpha1.PlannedAction
+		events  []event.Event
+	}
+
+	cases := map[string]struct {
+		reason string
+		global bool
+		cr     *v1alpha1.Project
+		o      managed.ExternalObservation
+		want   want
+	}{
+		"PassThrough": {
+			reason: "A resource that is not in dry-run mode should be observed as is, and have no planned action.",
+			cr:     project(withPlannedAction(v1alpha1.PlannedActionUpdate)),
+			o:      managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"PlanCreate": {
+			reason: "A resource that does not exist should be planned for creation and reported as existing.",
+			global: true,
+			cr:     project(),
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				planned: v1alpha1.PlannedActionCreate,
+				events:  []event.Event{event.Normal("PlannedCreate", "Dry run: would create the project in CodeFresh")},
+			},
+		},
+		"PlanUpdate": {
+			reason: "An outdated resource should be planned for update and reported as up to date.",
+			cr:     project(withDryRunAnnotation("true")),
+			o:      managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				planned: v1alpha1.PlannedActionUpdate,
+				events:  []event.Event{event.Normal("PlannedUpdate", "Dry run: would update the project in CodeFresh")},
+			},
+		},
+		"StillPlanned": {
+			reason: "An action that was already planned should not be reported again.",
+			global: true,
+			cr:     project(withPlannedAction(v1alpha1.PlannedActionUpdate)),
+			o:      managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				planned: v1alpha1.PlannedActionUpdate,
+			},
+		},
+		"PlanDelete": {
+			reason: "A deleted resource should be planned for deletion and reported as gone.",
+			global: true,
+			cr:     project(withDeletionTimestamp()),
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				o:       managed.ExternalObservation{},
+				planned: v1alpha1.PlannedActionDelete,
+				events:  []event.Event{event.Normal("PlannedDelete", "Dry run: would delete the project in CodeFresh")},
+			},
+		},
+		"PlanNone": {
+			reason: "An up to date resource should have no action planned, and emit no event.",
+			global: true,
+			cr:     project(withPlannedAction(v1alpha1.PlannedActionCreate)),
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				planned: v1alpha1.PlannedActionNone,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fail := func(op string) { t.Errorf("\n%s\nthe wrapped client should not be asked to %s", tc.reason, op) }
+			ec := &managed.ExternalClientFns{
+				ObserveFn: func(context.Context, resource.Managed) (managed.ExternalObservation, error) { return tc.o, nil },
+				CreateFn: func(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+					fail("Create")
+					return managed.ExternalCreation{}, nil
+				},
+				UpdateFn: func(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+					fail("Update")
+					return managed.ExternalUpdate{}, nil
+				},
+				DeleteFn: func(context.Context, resource.Managed) error {
+					fail("Delete")
+					return nil
+				},
+			}
+			r := &recorder{}
+			c := NewConnecter(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}), v1alpha1.ProjectKind, tc.global, r)
+
+			ctx := context.Background()
+			e, err := c.Connect(ctx, tc.cr)
+			if err != nil {
+				t.Fatalf("c.Connect(...): %v", err)
+			}
+			got, err := e.Observe(ctx, tc.cr)
+			if err != nil {
+				t.Fatalf("e.Observe(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want observation, +got observation:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.planned, tc.cr.Status.AtProvider.PlannedAction); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want planned action, +got planned action:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+
+			if tc.want.planned == "" {
+				return
+			}
+			// Even if the managed reconciler were to ask for a change, none
+			// is made.
+			if _, err := e.Create(ctx, tc.cr); err != nil {
+				t.Errorf("e.Create(...): %v", err)
+			}
+			if _, err := e.Update(ctx, tc.cr); err != nil {
+				t.Errorf("e.Update(...): %v", err)
+			}
+			if err := e.Delete(ctx, tc.cr); err != nil {
+				t.Errorf("e.Delete(...): %v", err)
+			}
+		})
+	}
+}
+
+func TestPlannedActionsMetric(t *testing.T) {
+	before := testutil.ToFloat64(PlannedActions.WithLabelValues("Metric", string(v1alpha1.PlannedActionUpdate)))
+
+	ec := &managed.ExternalClientFns{
+		ObserveFn: func(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+			return managed.ExternalObservation{ResourceExists: true}, nil
+		},
+	}
+	c := NewConnecter(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return ec, nil
+	}), "Metric", true, event.NewNopRecorder())
+
+	ctx := context.Background()
+	cr := project()
+	for i := 0; i < 3; i++ {
+		e, err := c.Connect(ctx, cr)
+		if err != nil {
+			t.Fatalf("c.Connect(...): %v", err)
+		}
+		if _, err := e.Observe(ctx, cr); err != nil {
+			t.Fatalf("e.Observe(...): %v", err)
+		}
+	}
+
+	got := testutil.ToFloat64(PlannedActions.WithLabelValues("Metric", string(v1alpha1.PlannedActionUpdate))) - before
+	if got != 1 {
+		t.Errorf("PlannedActions: want the planned update counted once, got %v", got)
+	}
+}
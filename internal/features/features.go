@@ -29,4 +29,9 @@ const (
 	// Management Policies. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/master/design/design-doc-observe-only-resources.md
 	EnableAlphaManagementPolicies feature.Flag = "EnableAlphaManagementPolicies"
+
+	// DryRun puts every resource in dry-run mode. The provider observes
+	// resources in CodeFresh and records the changes it would make, but
+	// never creates, updates or deletes them.
+	DryRun feature.Flag = "DryRun"
 )
@@ -0,0 +1,20 @@
+package helpers
+
+// LateInitializeStringPtr returns in if it is set, otherwise a pointer to
+// from, unless from is empty.
+func LateInitializeStringPtr(in *string, from string) *string {
+	if in != nil || from == "" {
+		return in
+	}
+	return &from
+}
+
+// LateInitializeStringSlice returns in if it has elements, otherwise from.
+func LateInitializeStringSlice(in, from []string) []string {
+	if len(in) != 0 || len(from) == 0 {
+		return in
+	}
+	out := make([]string, len(from))
+	copy(out, from)
+	return out
+}
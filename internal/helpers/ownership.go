@@ -0,0 +1,61 @@
+package helpers
+
+// OwnedOf returns the entries of observed that the provider manages in
+// Additive management mode: those whose key is declared or owned.
+func OwnedOf[T any](observed []T, declared, owned []string, key func(T) string) []T {
+	managed := keySet(declared, owned)
+	var out []T
+	for _, e := range observed {
+		if managed[key(e)] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// UnownedOf returns the entries of observed that the provider does not
+// manage in Additive management mode: those whose key is neither declared nor
+// owned.
+func UnownedOf[T any](observed []T, declared, owned []string, key func(T) string) []T {
+	managed := keySet(declared, owned)
+	var out []T
+	for _, e := range observed {
+		if !managed[key(e)] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// MergeOwned returns the declared entries followed by the entries of observed
+// that are neither declared nor owned, in the order they were observed. Owned
+// entries that are no longer declared are dropped.
+func MergeOwned[T any](declared, observed []T, owned []string, key func(T) string) []T {
+	keys := make([]string, len(declared))
+	for i, e := range declared {
+		keys[i] = key(e)
+	}
+	managed := keySet(keys, owned)
+	out := append([]T{}, declared...)
+	for _, e := range observed {
+		if !managed[key(e)] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Identity returns s. It is the key of a string entry.
+func Identity(s string) string {
+	return s
+}
+
+func keySet(lists ...[]string) map[string]bool {
+	set := map[string]bool{}
+	for _, l := range lists {
+		for _, k := range l {
+			set[k] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+const (
+	errGetVariableSecret    = "cannot get secret of variable"
+	errGetVariableConfigMap = "cannot get config map of variable"
+	errNoVariableSource     = "variable source sets neither a secret nor a config map key"
+	errFmtVariableKeyAbsent = "key %s not found in %s %s/%s"
+)
+
+// ResolveVariableValue returns the value of the Secret or ConfigMap key the
+// supplied source selects.
+func ResolveVariableValue(ctx context.Context, kube client.Reader, src *v1alpha1.VariableSource) (string, error) {
+	switch {
+	case src.SecretKeyRef != nil:
+		ref := src.SecretKeyRef
+		s := &corev1.Secret{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return "", errors.Wrap(err, errGetVariableSecret)
+		}
+		v, ok := s.Data[ref.Key]
+		if !ok {
+			return "", errors.Errorf(errFmtVariableKeyAbsent, ref.Key, "secret", ref.Namespace, ref.Name)
+		}
+		return string(v), nil
+	case src.ConfigMapKeyRef != nil:
+		ref := src.ConfigMapKeyRef
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+			return "", errors.Wrap(err, errGetVariableConfigMap)
+		}
+		v, ok := cm.Data[ref.Key]
+		if !ok {
+			return "", errors.Errorf(errFmtVariableKeyAbsent, ref.Key, "config map", ref.Namespace, ref.Name)
+		}
+		return v, nil
+	}
+	return "", errors.New(errNoVariableSource)
+}
+
+// VariableHash returns a hash of the value of a variable, salted with the UID
+// of the managed resource it belongs to, so that equal values of different
+// resources hash differently.
+func VariableHash(salt types.UID, key, value string) string {
+	h := sha256.New()
+	for _, s := range []string{string(salt), key, value} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
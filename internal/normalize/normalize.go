@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package normalize puts the fields of Project and Pipeline specs, as declared
+// and as observed in CodeFresh, into a canonical form before they are
+// compared. CodeFresh reorders lists whose order it ignores, fills in defaults
+// and accepts regexes in several forms; comparing canonical forms reports
+// drift only where CodeFresh would behave differently.
+//
+// Every function returns a copy and leaves its input unchanged. Applying a
+// function to its own result returns the result unchanged, and empty lists
+// and maps are returned as nil.
+package normalize
+
+import (
+	"sort"
+	"strings"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// Strings returns a sorted copy of a list whose order CodeFresh ignores, such
+// as tags. Duplicates are kept, so lists that differ in how often an entry
+// occurs remain different.
+func Strings(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+	return out
+}
+
+// Labels returns a copy of the supplied labels with the values of each label
+// normalized by Strings. A label without values is the same as a missing
+// label, so it is dropped.
+func Labels(in map[string][]string) map[string][]string {
+	var out map[string][]string
+	for k, v := range in {
+		if len(v) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string][]string, len(in))
+		}
+		out[k] = Strings(v)
+	}
+	return out
+}
+
+// PipelineVariables returns a copy of the supplied variables sorted by key,
+// then by value. CodeFresh ignores the order of variables.
+func PipelineVariables(in []v1alpha1.PipelineVariable) []v1alpha1.PipelineVariable {
+	if len(in) == 0 {
+		return nil
+	}
+	out := append([]v1alpha1.PipelineVariable(nil), in...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Key != out[j].Key {
+			return out[i].Key < out[j].Key
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// ProjectVariables returns a copy of the supplied variables sorted by key,
+// then by value. CodeFresh ignores the order of variables.
+func ProjectVariables(in []v1alpha1.ProjectVariable) []v1alpha1.ProjectVariable {
+	if len(in) == 0 {
+		return nil
+	}
+	out := append([]v1alpha1.ProjectVariable(nil), in...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Key != out[j].Key {
+			return out[i].Key < out[j].Key
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// Triggers returns a copy of the supplied triggers sorted by name, with their
+// branch and comment regexes normalized by Regex, their events by Strings and
+// their variables by PipelineVariables. CodeFresh ignores the order of
+// triggers.
+func Triggers(in []v1alpha1.PipelineTrigger) []v1alpha1.PipelineTrigger {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.PipelineTrigger, len(in))
+	for i, t := range in {
+		t.BranchRegex = Regex(t.BranchRegex)
+		t.CommentRegex = Regex(t.CommentRegex)
+		t.Events = Strings(t.Events)
+		t.Variables = PipelineVariables(t.Variables)
+		if len(t.Contexts) == 0 {
+			t.Contexts = nil
+		}
+		out[i] = t
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Steps returns a copy of the supplied steps with the values of each step
+// sorted by key, then by value. CodeFresh runs steps in order, so their order
+// is kept, but the values of a step are the fields of an object, whose order
+// it ignores.
+func Steps(in []v1alpha1.PipelineStep) []v1alpha1.PipelineStep {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.PipelineStep, len(in))
+	for i, st := range in {
+		if len(st.Values) == 0 {
+			st.Values = nil
+		} else {
+			st.Values = append([]v1alpha1.KeyValue(nil), st.Values...)
+			sort.SliceStable(st.Values, func(i, j int) bool {
+				a, b := st.Values[i], st.Values[j]
+				if a.Key != b.Key {
+					return a.Key < b.Key
+				}
+				return a.Value < b.Value
+			})
+		}
+		out[i] = st
+	}
+	return out
+}
+
+// SpecTemplate returns a copy of the supplied spec template with the location
+// and path CodeFresh defaults them to filled in.
+func SpecTemplate(in *v1alpha1.PipelineSpecTemplate) *v1alpha1.PipelineSpecTemplate {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	if out.Location == "" {
+		out.Location = v1alpha1.SpecTemplateLocationGit
+	}
+	if out.Path == "" {
+		out.Path = v1alpha1.SpecTemplatePathDefault
+	}
+	return &out
+}
+
+// ExternalResources returns a copy of the supplied external resources with
+// the type CodeFresh defaults them to filled in. CodeFresh copies external
+// resources in order, so their order is kept.
+func ExternalResources(in []v1alpha1.PipelineExternalResource) []v1alpha1.PipelineExternalResource {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.PipelineExternalResource, len(in))
+	for i, r := range in {
+		if r.Type == "" {
+			r.Type = v1alpha1.ExternalResourceTypeGit
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// TerminationRules returns a copy of the supplied termination rules with
+// their branch regexes normalized by Regex, sorted by type, event and key.
+// CodeFresh ignores the order of termination rules.
+func TerminationRules(in []v1alpha1.PipelineTerminationRule) []v1alpha1.PipelineTerminationRule {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.PipelineTerminationRule, len(in))
+	for i, r := range in {
+		r.BranchName = Regex(r.BranchName)
+		out[i] = r
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch {
+		case a.Type != b.Type:
+			return a.Type < b.Type
+		case a.Event != b.Event:
+			return a.Event < b.Event
+		case a.Key != b.Key:
+			return a.Key < b.Key
+		}
+		return a.BranchName < b.BranchName
+	})
+	return out
+}
+
+// Regex returns the canonical form of a CodeFresh regex, which may be written
+// either plainly or as a JavaScript literal such as /^dev-.*/gi: a literal
+// with its flags sorted and deduplicated. A plain regex is the same as a
+// literal without flags. Empty and unterminated literals are returned
+// unchanged.
+func Regex(expr string) string {
+	if expr == "" {
+		return ""
+	}
+	if !strings.HasPrefix(expr, "/") {
+		return "/" + expr + "/"
+	}
+	end := strings.LastIndex(expr, "/")
+	if end == 0 {
+		return expr
+	}
+	flags := strings.Split(expr[end+1:], "")
+	sort.Strings(flags)
+	var b strings.Builder
+	b.WriteString(expr[:end+1])
+	for i, f := range flags {
+		if i == 0 || f != flags[i-1] {
+			b.WriteString(f)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package normalize
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"crossplane-provider-codefresh/apis/resource/v1alpha1"
+)
+
+// The properties below are checked against inputs generated by testing/quick:
+// normalizing is idempotent, ignores the order of lists whose order CodeFresh
+// ignores, treats empty the same as unset, and leaves its input unchanged.
+
+// shuffled returns a copy of in in a random order.
+func shuffled[T any](r *rand.Rand, in []T) []T {
+	out := append([]T(nil), in...)
+	r.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// check reports a failed property with its reason.
+func check(t *testing.T, reason string, property any) {
+	t.Helper()
+	if err := quick.Check(property, nil); err != nil {
+		t.Errorf("\n%s\n%v", reason, err)
+	}
+}
+
+func TestStringsProperties(t *testing.T) {
+	check(t, "Strings should be idempotent.", func(in []string) bool {
+		return cmp.Equal(Strings(Strings(in)), Strings(in))
+	})
+	check(t, "Strings should ignore order.", func(in []string, seed int64) bool {
+		return cmp.Equal(Strings(shuffled(rand.New(rand.NewSource(seed)), in)), Strings(in))
+	})
+	check(t, "Strings should keep every entry, including duplicates.", func(in []string, extra string) bool {
+		return len(Strings(in)) == len(in) && !cmp.Equal(Strings(append(in, extra)), Strings(in))
+	})
+	check(t, "Strings should leave its input unchanged.", func(in []string) bool {
+		before := append([]string(nil), in...)
+		Strings(in)
+		return cmp.Equal(before, in, cmpopts.EquateEmpty())
+	})
+
+	if cmp.Equal(Strings([]string{"a", "a", "b"}), Strings([]string{"a", "b", "b"})) {
+		t.Error("Strings(...): lists with different duplicates should differ")
+	}
+	if got := Strings([]string{}); got != nil {
+		t.Errorf("Strings(...): want nil for an empty list, got %#v", got)
+	}
+}
+
+func TestLabelsProperties(t *testing.T) {
+	check(t, "Labels should be idempotent.", func(in map[string][]string) bool {
+		return cmp.Equal(Labels(Labels(in)), Labels(in))
+	})
+	check(t, "Labels should ignore the order of values.", func(in map[string][]string, seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		reordered := make(map[string][]string, len(in))
+		for k, v := range in {
+			reordered[k] = shuffled(r, v)
+		}
+		return cmp.Equal(Labels(reordered), Labels(in))
+	})
+	check(t, "Labels should treat a label without values as missing.", func(in map[string][]string, key string) bool {
+		if len(in[key]) > 0 {
+			return true
+		}
+		with := make(map[string][]string, len(in)+1)
+		for k, v := range in {
+			with[k] = v
+		}
+		with[key] = []string{}
+		return cmp.Equal(Labels(with), Labels(in))
+	})
+}
+
+func TestVariablesProperties(t *testing.T) {
+	check(t, "PipelineVariables should be idempotent.", func(in []v1alpha1.PipelineVariable) bool {
+		return cmp.Equal(PipelineVariables(PipelineVariables(in)), PipelineVariables(in))
+	})
+	check(t, "PipelineVariables should ignore order.", func(in []v1alpha1.PipelineVariable, seed int64) bool {
+		return cmp.Equal(PipelineVariables(shuffled(rand.New(rand.NewSource(seed)), in)), PipelineVariables(in))
+	})
+	check(t, "ProjectVariables should be idempotent.", func(in []v1alpha1.ProjectVariable) bool {
+		return cmp.Equal(ProjectVariables(ProjectVariables(in)), ProjectVariables(in))
+	})
+	check(t, "ProjectVariables should ignore order.", func(in []v1alpha1.ProjectVariable, seed int64) bool {
+		return cmp.Equal(ProjectVariables(shuffled(rand.New(rand.NewSource(seed)), in)), ProjectVariables(in))
+	})
+
+	a := []v1alpha1.ProjectVariable{{Key: "A", Value: "1"}, {Key: "A", Value: "1"}, {Key: "B", Value: "2"}}
+	b := []v1alpha1.ProjectVariable{{Key: "A", Value: "1"}, {Key: "B", Value: "2"}, {Key: "B", Value: "2"}}
+	if cmp.Equal(ProjectVariables(a), ProjectVariables(b)) {
+		t.Error("ProjectVariables(...): lists with different duplicates should differ")
+	}
+}
+
+func TestTriggersProperties(t *testing.T) {
+	check(t, "Triggers should be idempotent.", func(in []v1alpha1.PipelineTrigger) bool {
+		return cmp.Equal(Triggers(Triggers(in)), Triggers(in))
+	})
+	check(t, "Triggers should ignore the order of triggers with distinct names.", func(in []v1alpha1.PipelineTrigger, seed int64) bool {
+		seen := map[string]bool{}
+		var distinct []v1alpha1.PipelineTrigger
+		for _, tr := range in {
+			if !seen[tr.Name] {
+				seen[tr.Name] = true
+				distinct = append(distinct, tr)
+			}
+		}
+		return cmp.Equal(Triggers(shuffled(rand.New(rand.NewSource(seed)), distinct)), Triggers(distinct))
+	})
+
+	want := Triggers([]v1alpha1.PipelineTrigger{{Name: "push", BranchRegex: "^dev", CommentRegex: "/skip/gi", Events: []string{"push", "pr"}, Contexts: []v1alpha1.PipelineContext{}}})
+	got := Triggers([]v1alpha1.PipelineTrigger{{Name: "push", BranchRegex: "/^dev/", CommentRegex: "/skip/ig", Events: []string{"pr", "push"}}})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Triggers(...): regexes, events and contexts should be normalized: -want, +got:\n%s", diff)
+	}
+}
+
+func TestStepsProperties(t *testing.T) {
+	check(t, "Steps should be idempotent.", func(in []v1alpha1.PipelineStep) bool {
+		return cmp.Equal(Steps(Steps(in)), Steps(in))
+	})
+	check(t, "Steps should ignore the order of the values of a step.", func(in v1alpha1.PipelineStep, seed int64) bool {
+		reordered := in
+		reordered.Values = shuffled(rand.New(rand.NewSource(seed)), in.Values)
+		return cmp.Equal(Steps([]v1alpha1.PipelineStep{reordered}), Steps([]v1alpha1.PipelineStep{in}))
+	})
+	check(t, "Steps should leave its input unchanged.", func(in []v1alpha1.PipelineStep) bool {
+		before := make([]v1alpha1.PipelineStep, len(in))
+		for i, st := range in {
+			before[i] = *st.DeepCopy()
+		}
+		Steps(in)
+		return cmp.Equal(before, in, cmpopts.EquateEmpty())
+	})
+
+	a := []v1alpha1.PipelineStep{{Name: "build"}, {Name: "test"}}
+	b := []v1alpha1.PipelineStep{{Name: "test"}, {Name: "build"}}
+	if cmp.Equal(Steps(a), Steps(b)) {
+		t.Error("Steps(...): steps in another order should differ")
+	}
+}
+
+func TestDefaultsProperties(t *testing.T) {
+	check(t, "SpecTemplate should be idempotent.", func(in *v1alpha1.PipelineSpecTemplate) bool {
+		return cmp.Equal(SpecTemplate(SpecTemplate(in)), SpecTemplate(in))
+	})
+	check(t, "SpecTemplate should fill in the location and path CodeFresh defaults to.", func(in v1alpha1.PipelineSpecTemplate) bool {
+		unset, set := in, in
+		unset.Location, unset.Path = "", ""
+		set.Location, set.Path = v1alpha1.SpecTemplateLocationGit, v1alpha1.SpecTemplatePathDefault
+		return cmp.Equal(SpecTemplate(&unset), SpecTemplate(&set))
+	})
+	check(t, "ExternalResources should be idempotent.", func(in []v1alpha1.PipelineExternalResource) bool {
+		return cmp.Equal(ExternalResources(ExternalResources(in)), ExternalResources(in))
+	})
+	check(t, "ExternalResources should fill in the type CodeFresh defaults to.", func(in []v1alpha1.PipelineExternalResource) bool {
+		unset := make([]v1alpha1.PipelineExternalResource, len(in))
+		set := make([]v1alpha1.PipelineExternalResource, len(in))
+		for i, r := range in {
+			unset[i], set[i] = r, r
+			unset[i].Type, set[i].Type = "", v1alpha1.ExternalResourceTypeGit
+		}
+		return cmp.Equal(ExternalResources(unset), ExternalResources(set))
+	})
+
+	if got := ExternalResources([]v1alpha1.PipelineExternalResource{}); got != nil {
+		t.Errorf("ExternalResources(...): want nil for an empty list, got %#v", got)
+	}
+	if diff := cmp.Diff(ExternalResources(nil), ExternalResources([]v1alpha1.PipelineExternalResource{})); diff != "" {
+		t.Errorf("ExternalResources(...): empty and unset should be equal: -want, +got:\n%s", diff)
+	}
+}
+
+func TestTerminationRulesProperties(t *testing.T) {
+	check(t, "TerminationRules should be idempotent.", func(in []v1alpha1.PipelineTerminationRule) bool {
+		return cmp.Equal(TerminationRules(TerminationRules(in)), TerminationRules(in))
+	})
+	check(t, "TerminationRules should ignore order.", func(in []v1alpha1.PipelineTerminationRule, seed int64) bool {
+		return cmp.Equal(TerminationRules(shuffled(rand.New(rand.NewSource(seed)), in)), TerminationRules(in))
+	})
+
+	want := TerminationRules([]v1alpha1.PipelineTerminationRule{{Type: "branch", Event: "onCreate", BranchName: "/^dev/gi"}})
+	got := TerminationRules([]v1alpha1.PipelineTerminationRule{{Type: "branch", Event: "onCreate", BranchName: "/^dev/ig"}})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TerminationRules(...): branch regexes should be normalized: -want, +got:\n%s", diff)
+	}
+}
+
+func TestRegexProperties(t *testing.T) {
+	check(t, "Regex should be idempotent.", func(in string) bool {
+		return Regex(Regex(in)) == Regex(in)
+	})
+	check(t, "Regex should ignore the order and repetition of flags.", func(pattern string, flags []bool, seed int64) bool {
+		var f []string
+		for i, set := range flags {
+			if set {
+				f = append(f, string("gimsuy"[i%6]))
+			}
+		}
+		literal := "/" + pattern + "/" + strings.Join(f, "")
+		reordered := "/" + pattern + "/" + strings.Join(shuffled(rand.New(rand.NewSource(seed)), f), "")
+		return Regex(literal) == Regex(reordered)
+	})
+
+	cases := map[string]struct {
+		reason string
+		a, b   string
+		equal  bool
+	}{
+		"PlainAndLiteral": {
+			reason: "A plain regex should be the same as a literal without flags.",
+			a:      "^dev-.*",
+			b:      "/^dev-.*/",
+			equal:  true,
+		},
+		"Flags": {
+			reason: "Literals that differ in their flags should differ.",
+			a:      "/^dev/i",
+			b:      "/^dev/",
+		},
+		"Unterminated": {
+			reason: "An unterminated literal should be left unchanged.",
+			a:      "/^dev",
+			b:      "/^dev/",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Regex(tc.a) == Regex(tc.b); got != tc.equal {
+				t.Errorf("\n%s\nRegex(%q) == Regex(%q): want %t, got %t", tc.reason, tc.a, tc.b, tc.equal, got)
+			}
+		})
+	}
+}